@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"tressette-game/internal/database"
 	"tressette-game/internal/server"
@@ -14,7 +16,23 @@ func main() {
 	db := database.New()
 	defer db.Close()
 
-	hub := server.NewHub(&db)
+	config := server.Config{
+		ReconnectGracePeriod: server.DefaultReconnectGracePeriod,
+		TrickPause:           server.DefaultTrickPause,
+		ReadyTimeout:         server.DefaultReadyTimeout,
+	}
+	if grace, err := time.ParseDuration(os.Getenv("RECONNECT_GRACE_PERIOD")); err == nil {
+		config.ReconnectGracePeriod = grace
+	}
+	if pause, err := time.ParseDuration(os.Getenv("TRICK_PAUSE")); err == nil {
+		config.TrickPause = pause
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("READY_TIMEOUT")); err == nil {
+		config.ReadyTimeout = timeout
+	}
+
+	hub := server.NewHub(&db, config)
+	hub.RestoreTournaments()
 	go hub.Run()
 
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
@@ -24,8 +42,7 @@ func main() {
 	fs := http.FileServer(http.Dir("web/static"))
 	http.Handle("/", fs)
 
-	server.HandleRoutes(&db)
+	server.HandleRoutes(&db, hub)
 
 	log.Fatal(http.ListenAndServe(":8080", nil))
 }
-
@@ -16,10 +16,29 @@ type Service struct {
 }
 
 var (
-	tableName  = "tressette"
-	dbInstance *Service
+	tableName              = "tressette"
+	teamsTableName         = "tressette_teams"
+	eventsTableName        = "tressette_events"
+	matchesTableName       = "tressette_matches"
+	matchGamesTableName    = "tressette_match_games"
+	accountsTableName      = "tressette_accounts"
+	authTokensTableName    = "tressette_auth_tokens"
+	ratingsTableName       = "tressette_ratings"
+	ratingHistoryTableName = "tressette_rating_history"
+	tournamentsTableName   = "tressette_tournaments"
+	dbInstance             *Service
 )
 
+// resultColumns lists the columns of the results table in the order scanResult reads them.
+const resultColumns = "id, created_at, player_count, ruleset, player1, player2, player3, player4, " +
+	"player1_team, player2_team, player3_team, player4_team, team1_score, team2_score, " +
+	"player1_account, player2_account, player3_account, player4_account, ranked, forfeited_by"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 func New() Service {
 	var err error
 	db, err := sql.Open("sqlite3", "./tressette.db")
@@ -42,12 +61,95 @@ func New() Service {
 		team1_score integer,
 		team2_score integer
 	);
+	create table if not exists tressette_teams (
+		game_id string not null,
+		team_number integer not null,
+		score integer,
+		primary key (game_id, team_number)
+	);
+	create table if not exists tressette_events (
+		game_id string not null,
+		seq integer not null,
+		type string,
+		data string,
+		primary key (game_id, seq)
+	);
+	create table if not exists tressette_matches (
+		id string not null primary key,
+		created_at string,
+		best_of integer,
+		ruleset string,
+		status string,
+		winning_team integer
+	);
+	create table if not exists tressette_match_games (
+		match_id string not null,
+		seq integer not null,
+		game_id string,
+		winning_team integer,
+		primary key (match_id, seq)
+	);
+	create table if not exists tressette_accounts (
+		id string not null primary key,
+		username string not null unique collate nocase,
+		password_hash string not null,
+		created_at string
+	);
+	create table if not exists tressette_auth_tokens (
+		token string not null primary key,
+		account_id string not null,
+		created_at string
+	);
+	create table if not exists tressette_ratings (
+		account_id string not null primary key,
+		rating integer not null,
+		games integer not null
+	);
+	create table if not exists tressette_rating_history (
+		account_id string not null,
+		game_id string not null,
+		rating_before integer,
+		rating_after integer,
+		created_at string,
+		primary key (account_id, game_id)
+	);
+	create table if not exists tressette_tournaments (
+		id string not null primary key,
+		name string,
+		format string,
+		status string,
+		created_at string,
+		data string
+	);
 	`
 	_, err = db.Exec(sqlStmt)
 	if err != nil {
 		panic(err)
 	}
 
+	// Databases created before three-player games did not record the table size.
+	if err := addColumnIfMissing(db, tableName, "player_count", "integer not null default 4"); err != nil {
+		panic(err)
+	}
+	// Nor which game or variant was played.
+	if err := addColumnIfMissing(db, tableName, "ruleset", "string not null default 'classic'"); err != nil {
+		panic(err)
+	}
+	// Nor which registered accounts played; guests have none.
+	for _, column := range []string{"player1_account", "player2_account", "player3_account", "player4_account"} {
+		if err := addColumnIfMissing(db, tableName, column, "string not null default ''"); err != nil {
+			panic(err)
+		}
+	}
+	// Nor whether the game counted toward the ratings.
+	if err := addColumnIfMissing(db, tableName, "ranked", "boolean not null default 0"); err != nil {
+		panic(err)
+	}
+	// Nor whether it ended with a player leaving; forfeits were not stored at all.
+	if err := addColumnIfMissing(db, tableName, "forfeited_by", "string not null default ''"); err != nil {
+		panic(err)
+	}
+
 	dbInstance = &Service{
 		db:         db,
 		table_name: tableName,
@@ -57,6 +159,38 @@ func New() Service {
 	return *dbInstance
 }
 
+// addColumnIfMissing adds a column to an existing table unless it is already there.
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
+}
+
 func (s *Service) Close() error {
 	return s.db.Close()
 }
@@ -68,66 +202,51 @@ func (s *Service) TableName() string {
 func (s *Service) GetAll() ([]GameResult, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
-	rows, err := s.db.Query("SELECT * FROM " + s.table_name)
+	rows, err := s.db.Query("SELECT " + resultColumns + " FROM " + s.table_name)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var results []GameResult
-	for rows.Next() {
-		var result GameResult
-		if err := rows.Scan(
-			&result.ID,
-			&result.CreatedAt,
-			&result.Player1,
-			&result.Player2,
-			&result.Player3,
-			&result.Player4,
-			&result.Player1Team,
-			&result.Player2Team,
-			&result.Player3Team,
-			&result.Player4Team,
-			&result.Team1Score,
-			&result.Team2Score); err != nil {
-			return nil, err
-		}
-		results = append(results, result)
+	results, err := scanResults(rows)
+	if err != nil {
+		return nil, err
 	}
 
+	if err := s.loadScores(results); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
 func (s *Service) GetByID(id string) (GameResult, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
-	var result GameResult
-	err := s.db.QueryRow("SELECT * FROM "+s.table_name+" WHERE id = ?", id).Scan(
-		&result.ID,
-		&result.CreatedAt,
-		&result.Player1,
-		&result.Player2,
-		&result.Player3,
-		&result.Player4,
-		&result.Player1Team,
-		&result.Player2Team,
-		&result.Player3Team,
-		&result.Player4Team,
-		&result.Team1Score,
-		&result.Team2Score)
+	result, err := scanResult(s.db.QueryRow("SELECT "+resultColumns+" FROM "+s.table_name+" WHERE id = ?", id))
 	if err != nil {
 		return GameResult{}, err
 	}
-	return result, nil
+
+	results := []GameResult{result}
+	if err := s.loadScores(results); err != nil {
+		return GameResult{}, err
+	}
+	return results[0], nil
 }
 
 func (s *Service) Insert(result GameResult) error {
 	s.m.Lock()
 	defer s.m.Unlock()
-	_, err := s.db.Exec("INSERT INTO "+s.table_name+
-		" (id, created_at, player1, player2, player3, player4, player1_team, player2_team, player3_team, player4_team, team1_score, team2_score) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO "+s.table_name+
+		" ("+resultColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		result.ID,
 		result.CreatedAt,
+		result.PlayerCount,
+		result.Ruleset,
 		result.Player1,
 		result.Player2,
 		result.Player3,
@@ -137,19 +256,35 @@ func (s *Service) Insert(result GameResult) error {
 		result.Player3Team,
 		result.Player4Team,
 		result.Team1Score,
-		result.Team2Score)
+		result.Team2Score,
+		result.Player1Account,
+		result.Player2Account,
+		result.Player3Account,
+		result.Player4Account,
+		result.Ranked,
+		result.ForfeitedBy)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	for _, score := range result.Scores {
+		_, err = tx.Exec("INSERT INTO "+teamsTableName+" (game_id, team_number, score) VALUES (?, ?, ?)",
+			result.ID,
+			score.TeamNumber,
+			score.Score)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (s *Service) GetByPlayer(player_name string) ([]GameResult, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
-	rows, err := s.db.Query("SELECT * FROM "+s.table_name+
+	rows, err := s.db.Query("SELECT "+resultColumns+" FROM "+s.table_name+
 		" WHERE player1 = ? OR player2 = ? OR player3 = ? OR player4 = ?",
 		player_name,
 		player_name,
@@ -158,32 +293,460 @@ func (s *Service) GetByPlayer(player_name string) ([]GameResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	results, err := scanResults(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, sql.ErrNoRows // No results found
+	}
+
+	if err := s.loadScores(results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetByAccount returns the games a registered account played, whatever names it used.
+func (s *Service) GetByAccount(accountID string) ([]GameResult, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rows, err := s.db.Query("SELECT "+resultColumns+" FROM "+s.table_name+
+		" WHERE player1_account = ? OR player2_account = ? OR player3_account = ? OR player4_account = ?",
+		accountID,
+		accountID,
+		accountID,
+		accountID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := scanResults(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, sql.ErrNoRows // No results found
+	}
+
+	if err := s.loadScores(results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// InsertEvents stores the event log of a game.
+func (s *Service) InsertEvents(events []GameEvent) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		_, err = tx.Exec("INSERT INTO "+eventsTableName+" (game_id, seq, type, data) VALUES (?, ?, ?, ?)",
+			event.GameID,
+			event.Seq,
+			event.Type,
+			event.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetEvents returns the event log of a game in order.
+func (s *Service) GetEvents(gameID string) ([]GameEvent, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rows, err := s.db.Query("SELECT game_id, seq, type, data FROM "+eventsTableName+
+		" WHERE game_id = ? ORDER BY seq", gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []GameEvent
+	for rows.Next() {
+		var event GameEvent
+		if err := rows.Scan(&event.GameID, &event.Seq, &event.Type, &event.Data); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		return nil, sql.ErrNoRows // No log for this game
+	}
+	return events, nil
+}
+
+// InsertMatch stores a series and the games played in it.
+func (s *Service) InsertMatch(match MatchResult) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO "+matchesTableName+" (id, created_at, best_of, ruleset, status, winning_team) VALUES (?, ?, ?, ?, ?, ?)",
+		match.ID,
+		match.CreatedAt,
+		match.BestOf,
+		match.Ruleset,
+		match.Status,
+		match.WinningTeam)
+	if err != nil {
+		return err
+	}
+
+	for i, g := range match.Games {
+		_, err = tx.Exec("INSERT INTO "+matchGamesTableName+" (match_id, seq, game_id, winning_team) VALUES (?, ?, ?, ?)",
+			match.ID,
+			i,
+			g.GameID,
+			g.WinningTeam)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMatch returns a series with its games in the order they were played.
+func (s *Service) GetMatch(id string) (MatchResult, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var match MatchResult
+	err := s.db.QueryRow("SELECT id, created_at, best_of, ruleset, status, winning_team FROM "+matchesTableName+" WHERE id = ?", id).
+		Scan(&match.ID, &match.CreatedAt, &match.BestOf, &match.Ruleset, &match.Status, &match.WinningTeam)
+	if err != nil {
+		return MatchResult{}, err
+	}
+
+	rows, err := s.db.Query("SELECT game_id, winning_team FROM "+matchGamesTableName+" WHERE match_id = ? ORDER BY seq", id)
+	if err != nil {
+		return MatchResult{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var g MatchGame
+		if err := rows.Scan(&g.GameID, &g.WinningTeam); err != nil {
+			return MatchResult{}, err
+		}
+		match.Games = append(match.Games, g)
+	}
+	return match, rows.Err()
+}
+
+func scanResult(row rowScanner) (GameResult, error) {
+	var result GameResult
+	err := row.Scan(
+		&result.ID,
+		&result.CreatedAt,
+		&result.PlayerCount,
+		&result.Ruleset,
+		&result.Player1,
+		&result.Player2,
+		&result.Player3,
+		&result.Player4,
+		&result.Player1Team,
+		&result.Player2Team,
+		&result.Player3Team,
+		&result.Player4Team,
+		&result.Team1Score,
+		&result.Team2Score,
+		&result.Player1Account,
+		&result.Player2Account,
+		&result.Player3Account,
+		&result.Player4Account,
+		&result.Ranked,
+		&result.ForfeitedBy)
+	return result, err
+}
+
+func scanResults(rows *sql.Rows) ([]GameResult, error) {
 	defer rows.Close()
 
 	var results []GameResult
 	for rows.Next() {
-		var result GameResult
-		if err := rows.Scan(
-			&result.ID,
-			&result.CreatedAt,
-			&result.Player1,
-			&result.Player2,
-			&result.Player3,
-			&result.Player4,
-			&result.Player1Team,
-			&result.Player2Team,
-			&result.Player3Team,
-			&result.Player4Team,
-			&result.Team1Score,
-			&result.Team2Score); err != nil {
+		result, err := scanResult(rows)
+		if err != nil {
 			return nil, err
 		}
 		results = append(results, result)
 	}
+	return results, rows.Err()
+}
 
-	if len(results) == 0 {
-		return nil, sql.ErrNoRows // No results found
+// loadScores fills in the per-team scores of each result. Games recorded before
+// the teams table existed fall back to the two legacy score columns.
+func (s *Service) loadScores(results []GameResult) error {
+	for i := range results {
+		rows, err := s.db.Query("SELECT team_number, score FROM "+teamsTableName+
+			" WHERE game_id = ? ORDER BY team_number", results[i].ID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var score TeamScore
+			if err := rows.Scan(&score.TeamNumber, &score.Score); err != nil {
+				rows.Close()
+				return err
+			}
+			results[i].Scores = append(results[i].Scores, score)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		if len(results[i].Scores) == 0 {
+			results[i].Scores = []TeamScore{
+				{TeamNumber: 1, Score: results[i].Team1Score},
+				{TeamNumber: 2, Score: results[i].Team2Score},
+			}
+		}
 	}
+	return nil
+}
 
-	return results, nil
+// InsertAccount registers an account. Returns ErrUsernameTaken if the username
+// is in use, whatever its case.
+func (s *Service) InsertAccount(account Account) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var existing string
+	err := s.db.QueryRow("SELECT id FROM "+accountsTableName+" WHERE username = ?", account.Username).Scan(&existing)
+	if err == nil {
+		return ErrUsernameTaken
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	_, err = s.db.Exec("INSERT INTO "+accountsTableName+" (id, username, password_hash, created_at) VALUES (?, ?, ?, ?)",
+		account.ID,
+		account.Username,
+		account.PasswordHash,
+		account.CreatedAt)
+	return err
+}
+
+// GetAccountByUsername looks up an account, ignoring the case of the username.
+func (s *Service) GetAccountByUsername(username string) (Account, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var account Account
+	err := s.db.QueryRow("SELECT id, username, password_hash, created_at FROM "+accountsTableName+" WHERE username = ?", username).
+		Scan(&account.ID, &account.Username, &account.PasswordHash, &account.CreatedAt)
+	return account, err
+}
+
+// InsertAuthToken stores a login token for an account.
+func (s *Service) InsertAuthToken(token, accountID, createdAt string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	_, err := s.db.Exec("INSERT INTO "+authTokensTableName+" (token, account_id, created_at) VALUES (?, ?, ?)", token, accountID, createdAt)
+	return err
+}
+
+// GetAccountByToken returns the account a login token belongs to.
+func (s *Service) GetAccountByToken(token string) (Account, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var account Account
+	err := s.db.QueryRow("SELECT a.id, a.username, a.password_hash, a.created_at FROM "+accountsTableName+" a JOIN "+
+		authTokensTableName+" t ON t.account_id = a.id WHERE t.token = ?", token).
+		Scan(&account.ID, &account.Username, &account.PasswordHash, &account.CreatedAt)
+	return account, err
+}
+
+// DeleteAuthToken logs a token out.
+func (s *Service) DeleteAuthToken(token string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	_, err := s.db.Exec("DELETE FROM "+authTokensTableName+" WHERE token = ?", token)
+	return err
+}
+
+// GetRatings returns the ratings of the given accounts. Accounts that never
+// played a ranked game are left out.
+func (s *Service) GetRatings(accountIDs []string) (map[string]Rating, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	ratings := make(map[string]Rating)
+	for _, id := range accountIDs {
+		var r Rating
+		err := s.db.QueryRow("SELECT r.account_id, a.username, r.rating, r.games FROM "+ratingsTableName+" r JOIN "+
+			accountsTableName+" a ON a.id = r.account_id WHERE r.account_id = ?", id).
+			Scan(&r.AccountID, &r.Username, &r.Rating, &r.Games)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ratings[id] = r
+	}
+	return ratings, nil
+}
+
+// ApplyRatingChanges stores the new ratings after a ranked game and adds them
+// to each player's history.
+func (s *Service) ApplyRatingChanges(changes []RatingChange) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range changes {
+		_, err = tx.Exec("INSERT INTO "+ratingsTableName+" (account_id, rating, games) VALUES (?, ?, 1) "+
+			"ON CONFLICT(account_id) DO UPDATE SET rating = excluded.rating, games = games + 1",
+			c.AccountID,
+			c.RatingAfter)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("INSERT INTO "+ratingHistoryTableName+" (account_id, game_id, rating_before, rating_after, created_at) VALUES (?, ?, ?, ?, ?)",
+			c.AccountID,
+			c.GameID,
+			c.RatingBefore,
+			c.RatingAfter,
+			c.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLeaderboard returns the highest rated players, best first.
+func (s *Service) GetLeaderboard(limit int) ([]Rating, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rows, err := s.db.Query("SELECT r.account_id, a.username, r.rating, r.games FROM "+ratingsTableName+" r JOIN "+
+		accountsTableName+" a ON a.id = r.account_id ORDER BY r.rating DESC, r.games DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []Rating{}
+	for rows.Next() {
+		var r Rating
+		if err := rows.Scan(&r.AccountID, &r.Username, &r.Rating, &r.Games); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, r)
+	}
+	return ratings, rows.Err()
+}
+
+// GetRatingHistory returns how an account's rating changed, oldest game first.
+func (s *Service) GetRatingHistory(accountID string) ([]RatingChange, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rows, err := s.db.Query("SELECT account_id, game_id, rating_before, rating_after, created_at FROM "+ratingHistoryTableName+
+		" WHERE account_id = ? ORDER BY created_at, rowid", accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []RatingChange{}
+	for rows.Next() {
+		var c RatingChange
+		if err := rows.Scan(&c.AccountID, &c.GameID, &c.RatingBefore, &c.RatingAfter, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// SaveTournament stores a tournament, replacing its previous state.
+func (s *Service) SaveTournament(t TournamentRecord) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	_, err := s.db.Exec("INSERT INTO "+tournamentsTableName+" (id, name, format, status, created_at, data) VALUES (?, ?, ?, ?, ?, ?) "+
+		"ON CONFLICT (id) DO UPDATE SET name = excluded.name, status = excluded.status, data = excluded.data",
+		t.ID,
+		t.Name,
+		t.Format,
+		t.Status,
+		t.CreatedAt,
+		t.Data)
+	return err
+}
+
+// GetTournament returns a stored tournament.
+func (s *Service) GetTournament(id string) (TournamentRecord, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var t TournamentRecord
+	err := s.db.QueryRow("SELECT id, name, format, status, created_at, data FROM "+tournamentsTableName+" WHERE id = ?", id).
+		Scan(&t.ID, &t.Name, &t.Format, &t.Status, &t.CreatedAt, &t.Data)
+	if err != nil {
+		return TournamentRecord{}, err
+	}
+	return t, nil
+}
+
+// GetTournamentsByStatus returns every tournament with the given status, with its state.
+func (s *Service) GetTournamentsByStatus(status string) ([]TournamentRecord, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rows, err := s.db.Query("SELECT id, name, format, status, created_at, data FROM "+tournamentsTableName+" WHERE status = ? ORDER BY created_at", status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tournaments := []TournamentRecord{}
+	for rows.Next() {
+		var t TournamentRecord
+		if err := rows.Scan(&t.ID, &t.Name, &t.Format, &t.Status, &t.CreatedAt, &t.Data); err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+	return tournaments, rows.Err()
+}
+
+// GetTournaments lists every tournament, newest first, without its state.
+func (s *Service) GetTournaments() ([]TournamentRecord, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rows, err := s.db.Query("SELECT id, name, format, status, created_at FROM " + tournamentsTableName + " ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tournaments := []TournamentRecord{}
+	for rows.Next() {
+		var t TournamentRecord
+		if err := rows.Scan(&t.ID, &t.Name, &t.Format, &t.Status, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+	return tournaments, rows.Err()
 }
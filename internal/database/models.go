@@ -1,16 +1,107 @@
 package database
 
+import "errors"
+
 type GameResult struct {
-	ID          string `json:"id"`
-	CreatedAt   string `json:"created_at"`
-	Player1     string `json:"player1"`
-	Player2     string `json:"player2"`
-	Player3     string `json:"player3"`
-	Player4     string `json:"player4"`
-	Player1Team int    `json:"player1_team"`
-	Player2Team int    `json:"player2_team"`
-	Player3Team int    `json:"player3_team"`
-	Player4Team int    `json:"player4_team"`
-	Team1Score  int    `json:"team1_score"`
-	Team2Score  int    `json:"team2_score"`
+	ID          string      `json:"id"`
+	CreatedAt   string      `json:"created_at"`
+	PlayerCount int         `json:"player_count"`
+	Ruleset     string      `json:"ruleset"`
+	Player1     string      `json:"player1"`
+	Player2     string      `json:"player2"`
+	Player3     string      `json:"player3"`
+	Player4     string      `json:"player4"`
+	Player1Team int         `json:"player1_team"`
+	Player2Team int         `json:"player2_team"`
+	Player3Team int         `json:"player3_team"`
+	Player4Team int         `json:"player4_team"`
+	Team1Score  int         `json:"team1_score"`
+	Team2Score  int         `json:"team2_score"`
+	Scores      []TeamScore `json:"scores"` // Final score of every team, including a third in Terziglio
+
+	// Registered accounts of the players, empty for guests
+	Player1Account string `json:"player1_account,omitempty"`
+	Player2Account string `json:"player2_account,omitempty"`
+	Player3Account string `json:"player3_account,omitempty"`
+	Player4Account string `json:"player4_account,omitempty"`
+	Ranked         bool   `json:"ranked"`                 // Counted toward the players' ratings
+	ForfeitedBy    string `json:"forfeited_by,omitempty"` // Name of the player whose leaving ended the game, empty if it was played out
+}
+
+// TeamScore is the final score of one team in a finished game.
+type TeamScore struct {
+	TeamNumber int `json:"team_number"`
+	Score      int `json:"score"`
+}
+
+// GameEvent is one entry of a game's event log, stored as JSON.
+type GameEvent struct {
+	GameID string `json:"game_id"`
+	Seq    int    `json:"seq"`
+	Type   string `json:"type"`
+	Data   string `json:"data"`
+}
+
+// Status of a finished series.
+const (
+	MatchDecided   = "decided"   // A team won the series
+	MatchForfeit   = "forfeit"   // A player left; the other side won the series
+	MatchAbandoned = "abandoned" // The table broke up before the series was decided
+)
+
+// MatchResult is a best-of-N series played by the same table. Its games link to
+// the individual results by game ID.
+type MatchResult struct {
+	ID          string      `json:"id"`
+	CreatedAt   string      `json:"created_at"`
+	BestOf      int         `json:"best_of"`
+	Ruleset     string      `json:"ruleset"`
+	Status      string      `json:"status"`       // MatchDecided, MatchForfeit or MatchAbandoned
+	WinningTeam int         `json:"winning_team"` // 0 if abandoned
+	Games       []MatchGame `json:"games"`
+}
+
+// MatchGame is one game of a series.
+type MatchGame struct {
+	GameID      string `json:"game_id"`
+	WinningTeam int    `json:"winning_team"`
+}
+
+// ErrUsernameTaken is returned when registering a username that is already in use.
+var ErrUsernameTaken = errors.New("username already taken")
+
+// Account is a registered player. Guests play without one.
+type Account struct {
+	ID           string `json:"id"`
+	Username     string `json:"username"`
+	PasswordHash string `json:"-"` // bcrypt hash
+	CreatedAt    string `json:"created_at"`
+}
+
+// Rating is a registered player's current skill rating.
+type Rating struct {
+	AccountID string `json:"account_id"`
+	Username  string `json:"username"`
+	Rating    int    `json:"rating"`
+	Games     int    `json:"games"` // Ranked games played
+}
+
+// RatingChange is how one ranked game moved a player's rating.
+type RatingChange struct {
+	AccountID    string `json:"account_id"`
+	GameID       string `json:"game_id"`
+	RatingBefore int    `json:"rating_before"`
+	RatingAfter  int    `json:"rating_after"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// TournamentRecord is a stored tournament. Its pairs, rounds and results are
+// kept together as JSON in Data.
+type TournamentRecord struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Format    string `json:"format"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	Data      string `json:"-"`
 }
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"tressette-game/internal/protocol"
+)
+
+// agentThinkTime is how long an agent waits before acting, so humans can follow its plays.
+const agentThinkTime = 800 * time.Millisecond
+
+// Agent is a non-human participant occupying a seat. It receives exactly the
+// messages a human client gets (game_start, deal_hand, your_turn,
+// game_state_update, trick_end, ...) and answers with the actions it wants to
+// take, e.g. play_card or declare. Answers are validated like any client action.
+type Agent interface {
+	Receive(msg protocol.Message) []protocol.Message
+}
+
+// AttachAgent lets an agent control the seat of the given player.
+// Must be called before StartGameLoop.
+func (g *Game) AttachAgent(playerID string, agent Agent) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.agents[playerID] = agent
+}
+
+// IsAgent reports whether the player's seat is controlled by an agent.
+func (g *Game) IsAgent(playerID string) bool {
+	_, ok := g.agents[playerID]
+	return ok
+}
+
+// deliverToAgent hands a message to an agent and schedules its answers.
+// Assumes lock is held; the answers are played from a separate goroutine.
+func (g *Game) deliverToAgent(playerID string, agent Agent, message []byte) {
+	var msg protocol.Message
+	if err := json.Unmarshal(message, &msg); err != nil {
+		log.Printf("Game %s: Error decoding message for agent %s: %v", g.ID, playerID, err)
+		return
+	}
+
+	actions := agent.Receive(msg)
+	if len(actions) == 0 {
+		return
+	}
+	go func() {
+		time.Sleep(agentThinkTime)
+		for _, action := range actions {
+			g.HandlePlayerAction(playerID, action)
+		}
+	}()
+}
@@ -0,0 +1,500 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// HeuristicBot is a rule-of-thumb Tressette player. It follows suit, keeps
+// track of every card it has seen played, leads with cards that cannot be
+// beaten (3s, then 2s and aces once the higher cards are gone) and avoids
+// giving away its aces to the opponents. When playing a perdere it does the
+// opposite: it ducks under the winning card and dumps its points on others.
+type HeuristicBot struct {
+	playerID    string
+	seat        int
+	playerCount int
+	teamOf      map[int]int          // Seat -> team number, from game_start
+	seatIDs     []string             // Player ID of every seat, from game_start
+	hand        []shared.Card        // Cards currently held
+	played      map[shared.Card]bool // Cards seen on the table this round
+	table       []shared.Card        // Cards on the table in the current trick
+	declared    bool                 // Whether the bot has answered this round's declaration phase
+	misere      bool                 // Playing a perdere: avoid taking points
+	briscola    bool                 // Playing Briscola instead of Tressette
+	trump       shared.Suit          // Briscola trump suit of the current hand
+	eliminated  map[int]bool         // Seats knocked out of an "a perdere" game
+	partnerSeat int                  // "A chiamare": this round's partner, -1 while unknown
+}
+
+// NewHeuristicBot creates a bot for the seat of the given player.
+func NewHeuristicBot(playerID string) *HeuristicBot {
+	return &HeuristicBot{
+		playerID:    playerID,
+		seat:        -1,
+		teamOf:      make(map[int]int),
+		played:      make(map[shared.Card]bool),
+		eliminated:  make(map[int]bool),
+		partnerSeat: -1,
+	}
+}
+
+// Receive updates the bot's view of the table and answers the declaration phase and your_turn.
+func (b *HeuristicBot) Receive(msg protocol.Message) []protocol.Message {
+	switch msg.Type {
+	case "game_start":
+		var payload protocol.GameStartPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		b.playerCount = len(payload.Players)
+		b.misere = payload.Mode == string(ModeMisere)
+		b.briscola = payload.Ruleset == (&BriscolaRules{}).Name()
+		b.seatIDs = make([]string, len(payload.Players))
+		for _, p := range payload.Players {
+			b.seatIDs[p.Position] = p.ID
+			if p.ID == b.playerID {
+				b.seat = p.Position
+			}
+		}
+		for _, t := range payload.Teams {
+			for _, p := range t.Players {
+				b.teamOf[p.Position] = t.TeamNumber
+			}
+		}
+	case "deal_hand":
+		var payload protocol.DealHandPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		b.hand = append([]shared.Card{}, payload.Hand...)
+		b.declared = false
+		b.trump = ""
+		if payload.Trump != nil {
+			b.trump = payload.Trump.Suit
+		}
+		b.partnerSeat = -1
+		b.played = make(map[shared.Card]bool)
+		b.table = nil
+	case "game_state_update":
+		var payload protocol.GameStatePayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		b.table = payload.CardsOnTable
+		for _, c := range payload.CardsOnTable {
+			b.played[c] = true
+		}
+	case "trick_end":
+		var payload protocol.TrickEndPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		for _, c := range payload.Cards {
+			b.played[c] = true
+		}
+		b.table = nil
+	case "you_played":
+		var payload protocol.PlayerPlayedCardPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		b.removeFromHand(payload.Card)
+	case "card_drawn":
+		var payload protocol.CardDrawnPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if payload.PlayerID == b.playerID && payload.Card != nil {
+			b.hand = append(b.hand, *payload.Card)
+		}
+	case "player_eliminated":
+		var payload protocol.PlayerEliminatedPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if seat := b.seatOf(payload.PlayerID); seat != -1 {
+			b.eliminated[seat] = true
+		}
+	case "call_request":
+		return b.chooseCall()
+	case "card_called":
+		var payload protocol.CardCalledPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		// Holding the called card makes us the caller's partner
+		if payload.Card != nil && b.holds(payload.Card.Suit, payload.Card.Rank) {
+			b.partnerSeat = b.seatOf(payload.CallerID)
+		}
+	case "partner_revealed":
+		var payload protocol.PartnerRevealedPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if payload.CallerID == b.playerID {
+			b.partnerSeat = b.seatOf(payload.PartnerID)
+		}
+	case "declaration_phase":
+		var payload protocol.DeclarationPhasePayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		for _, id := range payload.PendingPlayerIDs {
+			if id == b.playerID && !b.declared {
+				return b.declareAll(payload.AvailableDeclarations)
+			}
+		}
+	case "ready_check":
+		var payload protocol.ReadyCheckPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		for _, id := range payload.PendingPlayerIDs {
+			if id == b.playerID {
+				if ready, err := newActionMessage("ready", nil); err == nil {
+					return []protocol.Message{ready}
+				}
+			}
+		}
+	case "your_turn":
+		return b.takeTurn()
+	case "error":
+		var payload protocol.ErrorPayload
+		if json.Unmarshal(msg.Payload, &payload) == nil {
+			log.Printf("Bot %s: server rejected action: %s", b.playerID, payload.Message)
+		}
+		// Answer the declaration phase again if it was rejected, e.g. while the game was paused
+		b.declared = false
+	}
+	return nil
+}
+
+// declareAll makes every declaration the server offers, then confirms it is done.
+func (b *HeuristicBot) declareAll(available []protocol.DeclarePayload) []protocol.Message {
+	b.declared = true
+	var actions []protocol.Message
+	for _, d := range available {
+		if msg, err := newActionMessage("declare", d); err == nil {
+			actions = append(actions, msg)
+		}
+	}
+	if msg, err := newActionMessage("declarations_done", nil); err == nil {
+		actions = append(actions, msg)
+	}
+	return actions
+}
+
+// takeTurn plays a card.
+func (b *HeuristicBot) takeTurn() []protocol.Message {
+	card, ok := b.chooseCard()
+	if !ok {
+		return nil
+	}
+	msg, err := newActionMessage("play_card", protocol.PlayCardPayload{Suit: card.Suit, Rank: card.Rank})
+	if err != nil {
+		return nil
+	}
+	return []protocol.Message{msg}
+}
+
+// chooseCall calls the highest card missing from our longest suit, or plays alone with all four 3s.
+func (b *HeuristicBot) chooseCall() []protocol.Message {
+	call := protocol.CallCardPayload{Alone: true}
+	threes := 0
+	for _, c := range b.hand {
+		if c.Rank == "3" {
+			threes++
+		}
+	}
+	if threes < 4 {
+		var longest []shared.Card
+		var longestSuit shared.Suit
+		for _, suit := range []shared.Suit{shared.Denari, shared.Spade, shared.Bastoni, shared.Kope} {
+			if cards := b.cardsOfSuit(suit); len(cards) > len(longest) || longestSuit == "" {
+				longest = cards
+				longestSuit = suit
+			}
+		}
+		for _, rank := range []string{"3", "2", "1", "13", "12", "11", "7", "6", "5", "4"} {
+			if !b.holds(longestSuit, rank) {
+				call = protocol.CallCardPayload{Suit: longestSuit, Rank: rank}
+				break
+			}
+		}
+	}
+
+	msg, err := newActionMessage("call_card", call)
+	if err != nil {
+		return nil
+	}
+	return []protocol.Message{msg}
+}
+
+// chooseCard picks the card to play from the current hand.
+func (b *HeuristicBot) chooseCard() (shared.Card, bool) {
+	if len(b.hand) == 0 {
+		return shared.Card{}, false
+	}
+	if b.misere {
+		return b.chooseMisereCard(), true
+	}
+	if b.briscola {
+		return b.chooseBriscolaCard(), true
+	}
+	if len(b.table) == 0 {
+		return b.chooseLead(), true
+	}
+
+	ledSuit := b.table[0].Suit
+	following := b.cardsOfSuit(ledSuit)
+	winning, winnerSeat := b.currentWinner()
+	partnerWinning := b.isPartner(winnerSeat)
+	lastToPlay := b.lastToPlay()
+
+	if len(following) == 0 {
+		// Void in the led suit: feed points to a partner who will take the trick,
+		// otherwise throw away the cheapest card.
+		if partnerWinning && (lastToPlay || b.isMaster(winning)) {
+			return highestValue(b.hand), true
+		}
+		return cheapest(b.discardCandidates()), true
+	}
+
+	if partnerWinning && (lastToPlay || b.isMaster(winning)) {
+		return highestValue(following), true
+	}
+
+	// Try to take the trick with a card nobody after us can beat
+	var winners []shared.Card
+	for _, c := range following {
+		if c.Order > winning.Order && (lastToPlay || b.isMaster(c)) {
+			winners = append(winners, c)
+		}
+	}
+	if len(winners) > 0 {
+		return lowestOrder(winners), true
+	}
+	return cheapest(following), true
+}
+
+// chooseMisereCard picks a card when playing a perdere: lead low, duck under the
+// winning card when possible and throw away points when void in the led suit.
+func (b *HeuristicBot) chooseMisereCard() shared.Card {
+	if len(b.table) == 0 {
+		return lowestOrder(b.hand)
+	}
+
+	following := b.cardsOfSuit(b.table[0].Suit)
+	if len(following) == 0 {
+		return highestValue(b.hand)
+	}
+
+	winning, _ := b.currentWinner()
+	var ducks []shared.Card
+	for _, c := range following {
+		if c.Order < winning.Order {
+			ducks = append(ducks, c)
+		}
+	}
+	if len(ducks) > 0 {
+		return highestOrder(ducks)
+	}
+	if b.lastToPlay() {
+		// The trick is ours anyway, so get rid of the strongest card
+		return highestOrder(following)
+	}
+	return lowestOrder(following)
+}
+
+// chooseLead picks the opening card of a trick.
+func (b *HeuristicBot) chooseLead() shared.Card {
+	// Cards that are guaranteed to win: the 3s, and 2s or aces once everything above is gone.
+	var masters []shared.Card
+	for _, c := range b.hand {
+		if b.isMaster(c) && c.Value > 0 {
+			masters = append(masters, c)
+		}
+	}
+	if len(masters) > 0 {
+		return highestOrder(masters)
+	}
+
+	// Otherwise lead low from the longest suit, keeping suits that guard an ace.
+	var best []shared.Card
+	for _, suit := range []shared.Suit{shared.Denari, shared.Spade, shared.Bastoni, shared.Kope} {
+		cards := b.cardsOfSuit(suit)
+		if len(cards) == 0 || (b.holds(suit, "1") && len(cards) <= 2) {
+			continue
+		}
+		if len(cards) > len(best) {
+			best = cards
+		}
+	}
+	if len(best) > 0 {
+		return cheapest(best)
+	}
+	return cheapest(b.hand)
+}
+
+// discardCandidates returns the cards that are safe to throw away, keeping aces if possible.
+func (b *HeuristicBot) discardCandidates() []shared.Card {
+	var candidates []shared.Card
+	for _, c := range b.hand {
+		if c.Rank != "1" {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return b.hand
+	}
+	return candidates
+}
+
+// currentWinner returns the winning card of the trick in progress and the seat that played it.
+func (b *HeuristicBot) currentWinner() (shared.Card, int) {
+	// Walk back from our seat to find who played each card on the table
+	seats := make([]int, len(b.table))
+	seat := b.seat
+	for i := len(b.table) - 1; i >= 0; i-- {
+		seat = b.previousSeat(seat)
+		seats[i] = seat
+	}
+
+	trick := shared.NewTrick()
+	for i, c := range b.table {
+		trick.AddCard(c, seats[i])
+	}
+	var winner shared.PlayedCard
+	if b.briscola {
+		winner = trick.DetermineWinnerWithTrump(b.trump, shared.Card.BriscolaOrder)
+	} else {
+		winner = trick.DetermineWinnerWithTrump("", func(c shared.Card) int { return c.Order })
+	}
+	return winner.Card, winner.PlayerIndex
+}
+
+// previousSeat returns the closest seat before seat that is still in the game.
+func (b *HeuristicBot) previousSeat(seat int) int {
+	for i := 1; i <= b.playerCount; i++ {
+		prev := (seat - i + b.playerCount) % b.playerCount
+		if !b.eliminated[prev] {
+			return prev
+		}
+	}
+	return seat
+}
+
+// lastToPlay reports whether the bot closes the trick in progress.
+func (b *HeuristicBot) lastToPlay() bool {
+	return len(b.table) == b.playerCount-len(b.eliminated)-1
+}
+
+// seatOf returns the seat of the given player, or -1 if the bot hasn't seen them.
+func (b *HeuristicBot) seatOf(playerID string) int {
+	for seat, id := range b.seatIDs {
+		if id == playerID {
+			return seat
+		}
+	}
+	return -1
+}
+
+// isMaster reports whether no unseen card of the same suit can beat c.
+func (b *HeuristicBot) isMaster(c shared.Card) bool {
+	for _, other := range shared.NewDeck().Cards {
+		if other.Suit != c.Suit || other.Order <= c.Order {
+			continue
+		}
+		if !b.played[other] && !b.holds(other.Suit, other.Rank) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *HeuristicBot) isPartner(seat int) bool {
+	return seat != b.seat && (b.teamOf[seat] == b.teamOf[b.seat] || seat == b.partnerSeat)
+}
+
+func (b *HeuristicBot) holds(suit shared.Suit, rank string) bool {
+	for _, c := range b.hand {
+		if c.Suit == suit && c.Rank == rank {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *HeuristicBot) cardsOfSuit(suit shared.Suit) []shared.Card {
+	var cards []shared.Card
+	for _, c := range b.hand {
+		if c.Suit == suit {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+func (b *HeuristicBot) removeFromHand(card shared.Card) {
+	for i, c := range b.hand {
+		if c == card {
+			b.hand = append(b.hand[:i], b.hand[i+1:]...)
+			return
+		}
+	}
+}
+
+// cheapest returns the card worth the fewest points, breaking ties by the lowest order.
+func cheapest(cards []shared.Card) shared.Card {
+	best := cards[0]
+	for _, c := range cards[1:] {
+		if c.Value < best.Value || (c.Value == best.Value && c.Order < best.Order) {
+			best = c
+		}
+	}
+	return best
+}
+
+// highestValue returns the card worth the most points, breaking ties by the lowest order.
+func highestValue(cards []shared.Card) shared.Card {
+	best := cards[0]
+	for _, c := range cards[1:] {
+		if c.Value > best.Value || (c.Value == best.Value && c.Order < best.Order) {
+			best = c
+		}
+	}
+	return best
+}
+
+func lowestOrder(cards []shared.Card) shared.Card {
+	best := cards[0]
+	for _, c := range cards[1:] {
+		if c.Order < best.Order {
+			best = c
+		}
+	}
+	return best
+}
+
+func highestOrder(cards []shared.Card) shared.Card {
+	best := cards[0]
+	for _, c := range cards[1:] {
+		if c.Order > best.Order {
+			best = c
+		}
+	}
+	return best
+}
+
+// newActionMessage wraps an action payload the way a client would send it.
+func newActionMessage(msgType string, payload interface{}) (protocol.Message, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return protocol.Message{}, err
+	}
+	return protocol.Message{Type: msgType, Payload: payloadBytes}, nil
+}
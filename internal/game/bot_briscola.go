@@ -0,0 +1,100 @@
+package game
+
+import (
+	"tressette-game/internal/shared"
+)
+
+// chooseBriscolaCard picks a card when playing Briscola: lead cheap cards, take
+// tricks with the led suit where possible, spend trumps only on valuable tricks
+// and load points onto a trick the partner is sure to win.
+func (b *HeuristicBot) chooseBriscolaCard() shared.Card {
+	if len(b.table) == 0 {
+		return b.cheapestBriscola(b.hand)
+	}
+
+	winning, winnerSeat := b.currentWinner()
+	if b.isPartner(winnerSeat) && b.lastToPlay() {
+		return b.richestBriscola(b.hand)
+	}
+
+	tablePoints := 0
+	for _, c := range b.table {
+		tablePoints += c.BriscolaPoints()
+	}
+
+	var suitWinners, trumpWinners []shared.Card
+	for _, c := range b.hand {
+		if !b.beats(c, winning) {
+			continue
+		}
+		if c.Suit == b.trump {
+			trumpWinners = append(trumpWinners, c)
+		} else {
+			suitWinners = append(suitWinners, c)
+		}
+	}
+	if len(suitWinners) > 0 {
+		return b.richestBriscola(suitWinners)
+	}
+	if len(trumpWinners) > 0 && (tablePoints >= 10 || (b.lastToPlay() && tablePoints > 0)) {
+		return lowestBriscolaOrder(trumpWinners)
+	}
+	return b.cheapestBriscola(b.hand)
+}
+
+// beats reports whether c would take the trick from the winning card.
+func (b *HeuristicBot) beats(c, winning shared.Card) bool {
+	if c.Suit == winning.Suit {
+		return c.BriscolaOrder() > winning.BriscolaOrder()
+	}
+	return c.Suit == b.trump
+}
+
+// cheapestBriscola returns the least valuable card, keeping trumps if possible.
+func (b *HeuristicBot) cheapestBriscola(cards []shared.Card) shared.Card {
+	var best *shared.Card
+	for i := range cards {
+		c := &cards[i]
+		if best == nil || b.briscolaCost(*c) < b.briscolaCost(*best) {
+			best = c
+		}
+	}
+	return *best
+}
+
+// richestBriscola returns the non-trump card worth the most points, or the cheapest card if none is.
+func (b *HeuristicBot) richestBriscola(cards []shared.Card) shared.Card {
+	var best *shared.Card
+	for i := range cards {
+		c := &cards[i]
+		if c.Suit == b.trump {
+			continue
+		}
+		if best == nil || c.BriscolaPoints() > best.BriscolaPoints() {
+			best = c
+		}
+	}
+	if best == nil {
+		return b.cheapestBriscola(cards)
+	}
+	return *best
+}
+
+// briscolaCost ranks how much it hurts to give a card away.
+func (b *HeuristicBot) briscolaCost(c shared.Card) int {
+	cost := c.BriscolaPoints()*10 + c.BriscolaOrder()
+	if c.Suit == b.trump {
+		cost += 1000
+	}
+	return cost
+}
+
+func lowestBriscolaOrder(cards []shared.Card) shared.Card {
+	best := cards[0]
+	for _, c := range cards[1:] {
+		if c.BriscolaOrder() < best.BriscolaOrder() {
+			best = c
+		}
+	}
+	return best
+}
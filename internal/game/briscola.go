@@ -0,0 +1,74 @@
+package game
+
+import (
+	"tressette-game/internal/shared"
+)
+
+// Briscola is played with the same deck as Tressette but with a trump suit,
+// three-card hands refilled from the stock after every trick and its own card
+// values (120 points in the deck). There is no obligation to follow suit.
+// Whoever takes more than half of the points wins the hand, and the first
+// side to win BriscolaHandsToWin hands wins the game.
+
+// BriscolaCardsPerPlayer is the Briscola hand size.
+const BriscolaCardsPerPlayer = 3
+
+// BriscolaHandsToWin is the number of hands needed to win a game of Briscola.
+const BriscolaHandsToWin = 2
+
+// BriscolaRules implements Ruleset for Briscola with two or four players.
+type BriscolaRules struct{}
+
+func (r *BriscolaRules) Name() string        { return "briscola" }
+func (r *BriscolaRules) Description() string { return "Briscola: trumps, three cards in hand" }
+func (r *BriscolaRules) Mode() Mode          { return ModeClassic }
+
+func (r *BriscolaRules) SupportsPlayerCount(players int) bool {
+	return players == TwoPlayers || players == FourPlayers
+}
+
+// TargetScore ignores the lobby's points goal, Briscola is played in hands.
+func (r *BriscolaRules) TargetScore(requested int) int { return BriscolaHandsToWin }
+
+func (r *BriscolaRules) HandSize(players int) int { return BriscolaCardsPerPlayer }
+func (r *BriscolaRules) HasTrump() bool           { return true }
+func (r *BriscolaRules) RevealsDraws() bool       { return false }
+
+// IsValidPlay allows any card, Briscola never forces following suit.
+func (r *BriscolaRules) IsValidPlay(hand []shared.Card, card shared.Card, trick TrickState) bool {
+	return true
+}
+
+func (r *BriscolaRules) TrickWinner(trick *shared.Trick, state TrickState) shared.PlayedCard {
+	return trick.DetermineWinnerWithTrump(state.Trump, shared.Card.BriscolaOrder)
+}
+
+// TrickPoints scales Briscola points by 3 like the rest of the scoring code.
+func (r *BriscolaRules) TrickPoints(cards []shared.Card) int {
+	points := 0
+	for _, card := range cards {
+		points += card.BriscolaPoints()
+	}
+	return points * 3
+}
+
+func (r *BriscolaRules) LastTrickBonus() int { return 0 }
+
+// SettleRound gives the hand to the side that took the most points; a 60-60 draw scores nothing.
+func (r *BriscolaRules) SettleRound(scores []int) []int {
+	settled := make([]int, len(scores))
+	if winner := highestAtTarget(scores, 0); winner != -1 {
+		settled[winner] = 3 // One hand, scaled
+	}
+	return settled
+}
+
+func (r *BriscolaRules) AllowsDeclarations() bool { return false }
+
+func (r *BriscolaRules) Declare(player *shared.Player, declaration shared.Declaration) shared.DeclarationResult {
+	return shared.DeclarationResult{Success: false}
+}
+
+func (r *BriscolaRules) Winner(totals []int, target int) int {
+	return highestAtTarget(totals, target)
+}
@@ -0,0 +1,210 @@
+package game
+
+import (
+	"log"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// Tressette a chiamare is a four-player game without fixed teams. At the start of
+// every round the first player calls a card they don't hold, and whoever holds it
+// becomes their secret partner until the card is played. Players are scored
+// individually, each getting their side's points for the round. A caller holding
+// all four 3s may play alone against the other three and scores their points twice.
+// Since the caller and their partner score the same points, players often tie at
+// the top; see chiamareTiebreak.
+
+// startCall opens the calling phase for the player who leads the round. Assumes lock is held.
+func (g *Game) startCall() {
+	g.GameState = Calling
+	g.callerIndex = g.PlayerTurnIndex
+	g.calledCard = nil
+	g.playsAlone = false
+	g.partnerIndex = -1
+	g.partnerRevealed = false
+	log.Printf("Game %s: Player %d (%s) is calling a partner.", g.ID, g.callerIndex, g.Players[g.callerIndex].Name)
+
+	g.startTurnClock()
+	g.broadcastGameState()
+	g.requestCall()
+}
+
+// requestCall asks the caller to name a card. Assumes lock is held.
+func (g *Game) requestCall() {
+	caller := g.Players[g.callerIndex]
+	payload := protocol.YourTurnPayload{
+		PlayerID:    caller.ID,
+		SecondsLeft: g.turnSecondsLeft(),
+	}
+	if g.timeBank > 0 {
+		payload.TimeBankSeconds = int(g.timeBanks[g.callerIndex].Seconds())
+	}
+	msg, _ := protocol.NewMessage("call_request", payload)
+	g.sendToPlayer(caller.ID, msg)
+}
+
+// handleCall validates the caller's choice and starts play. Assumes lock is held.
+func (g *Game) handleCall(playerIndex int, payload protocol.CallCardPayload) {
+	caller := g.Players[playerIndex]
+
+	if payload.Alone {
+		if !holdsAllThrees(caller) {
+			log.Printf("Game %s: Player %s tried to play alone without all four 3s.", g.ID, caller.ID)
+			g.sendErrorToPlayer(caller.ID, "You can only play alone with all four 3s.")
+			return
+		}
+		g.playsAlone = true
+		log.Printf("Game %s: Player %d (%s) plays alone.", g.ID, playerIndex, caller.Name)
+	} else {
+		card, ok := findDeckCard(payload.Suit, payload.Rank)
+		if !ok {
+			log.Printf("Game %s: Player %s called an unknown card %s %s.", g.ID, caller.ID, payload.Rank, payload.Suit)
+			g.sendErrorToPlayer(caller.ID, "Unknown card.")
+			return
+		}
+		if _, inHand := caller.FindCard(card.Suit, card.Rank); inHand {
+			g.sendErrorToPlayer(caller.ID, "You must call a card you don't hold.")
+			return
+		}
+		g.calledCard = &card
+		for i, p := range g.Players {
+			if _, holds := p.FindCard(card.Suit, card.Rank); holds {
+				g.partnerIndex = i
+			}
+		}
+		log.Printf("Game %s: Player %d (%s) called %s %s. Partner is player %d.", g.ID, playerIndex, caller.Name, card.Rank, card.Suit, g.partnerIndex)
+	}
+
+	g.stopTurnClock()
+	calledMsg, _ := protocol.NewMessage("card_called", g.callPayload())
+	g.broadcast(calledMsg)
+	g.logEvent(Event{Type: EventCall, Seat: playerIndex, Card: g.calledCard, Alone: g.playsAlone})
+
+	g.startDeclarations()
+}
+
+// revealPartnerIfCalled announces the partnership when the called card is played. Assumes lock is held.
+func (g *Game) revealPartnerIfCalled(card shared.Card) {
+	if g.Mode != ModeChiamare || g.partnerRevealed || g.calledCard == nil || *g.calledCard != card {
+		return
+	}
+	g.partnerRevealed = true
+	log.Printf("Game %s: Partner revealed: player %d (%s).", g.ID, g.partnerIndex, g.Players[g.partnerIndex].Name)
+
+	payload := protocol.PartnerRevealedPayload{
+		CallerID:  g.Players[g.callerIndex].ID,
+		PartnerID: g.Players[g.partnerIndex].ID,
+	}
+	msg, _ := protocol.NewMessage("partner_revealed", payload)
+	g.broadcast(msg)
+}
+
+// settlePartnerships replaces every player's round score with the score of their side.
+// Assumes lock is held.
+func (g *Game) settlePartnerships() {
+	var callerSide, otherSide int
+	for i := range g.Players {
+		if g.onCallerSide(i) {
+			callerSide += g.teamOf(i).Score
+		} else {
+			otherSide += g.teamOf(i).Score
+		}
+	}
+	if g.playsAlone {
+		callerSide *= 2
+	}
+
+	for i := range g.Players {
+		team := g.teamOf(i)
+		team.ResetScore()
+		if g.onCallerSide(i) {
+			team.AddScore(callerSide)
+		} else {
+			team.AddScore(otherSide)
+		}
+	}
+	log.Printf("Game %s: Caller's side scored %d (scaled), the others %d.", g.ID, callerSide, otherSide)
+}
+
+// chiamareTiebreak picks the winner when several players share the highest
+// total at or above the target. The tie goes to the last round's caller, then
+// to whoever took its last trick, then to the first tied player in playing
+// order from the caller. Returns -1 if nobody reached the target.
+// Assumes lock is held.
+func (g *Game) chiamareTiebreak(totals []int, target int) int {
+	high := totals[0]
+	for _, total := range totals[1:] {
+		if total > high {
+			high = total
+		}
+	}
+	if high < target {
+		return -1
+	}
+
+	tied := func(seat int) bool {
+		return seat >= 0 && seat < len(totals) && totals[seat] == high
+	}
+	if tied(g.callerIndex) {
+		return g.callerIndex
+	}
+	if tied(g.LastTrickWinnerIndex) {
+		return g.LastTrickWinnerIndex
+	}
+	for i := range totals {
+		if seat := (g.callerIndex + i) % len(totals); tied(seat) {
+			return seat
+		}
+	}
+	return -1
+}
+
+func (g *Game) onCallerSide(playerIndex int) bool {
+	return playerIndex == g.callerIndex || playerIndex == g.partnerIndex
+}
+
+// callPayload describes this round's call, or returns nil before the call is made.
+// Assumes lock is held.
+func (g *Game) callPayload() *protocol.CardCalledPayload {
+	if g.Mode != ModeChiamare || (g.calledCard == nil && !g.playsAlone) {
+		return nil
+	}
+	return &protocol.CardCalledPayload{
+		CallerID: g.Players[g.callerIndex].ID,
+		Card:     g.calledCard,
+		Alone:    g.playsAlone,
+	}
+}
+
+// knownPartnerID returns the caller's partner if the given player is allowed to know it.
+// Assumes lock is held.
+func (g *Game) knownPartnerID(playerIndex int) string {
+	if g.Mode != ModeChiamare || g.partnerIndex == -1 {
+		return ""
+	}
+	if g.partnerRevealed || playerIndex == g.partnerIndex {
+		return g.Players[g.partnerIndex].ID
+	}
+	return ""
+}
+
+func holdsAllThrees(player *shared.Player) bool {
+	count := 0
+	for _, c := range player.Hand {
+		if c.Rank == "3" {
+			count++
+		}
+	}
+	return count == 4
+}
+
+// findDeckCard looks up a card of the standard deck by suit and rank.
+func findDeckCard(suit shared.Suit, rank string) (shared.Card, bool) {
+	for _, c := range shared.NewDeck().Cards {
+		if c.Suit == suit && c.Rank == rank {
+			return c, true
+		}
+	}
+	return shared.Card{}, false
+}
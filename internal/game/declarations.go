@@ -0,0 +1,165 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"tressette-game/internal/protocol"
+)
+
+// DefaultDeclarationTimeout is how long the declaration phase lasts when the settings don't say.
+const DefaultDeclarationTimeout = 30 * time.Second
+
+// startDeclarations opens the declaration phase after the deal. Every player still
+// in may declare at the same time and then confirms they are done; play starts
+// once everyone has confirmed or the timer runs out. If the timer runs out while
+// a seat is held for a reconnect, ReconnectPlayer ends the phase instead.
+// Rulesets without declarations go straight to play. Assumes lock is held.
+func (g *Game) startDeclarations() {
+	if !g.Rules.AllowsDeclarations() {
+		g.beginPlay()
+		return
+	}
+
+	g.GameState = Declaring
+	g.pendingDeclarations = make([][]protocol.DeclarationConfirmationPayload, len(g.Players))
+	g.declarationsDone = make([]bool, len(g.Players))
+	for i := range g.Players {
+		g.declarationsDone[i] = g.eliminated[i]
+	}
+	g.declarationPhase++
+	g.declarationDeadline = time.Now().Add(g.declarationTimeout)
+	phase := g.declarationPhase
+	log.Printf("Game %s: Declaration phase started (%s).", g.ID, g.declarationTimeout)
+
+	time.AfterFunc(g.declarationTimeout, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.GameState != Declaring || g.declarationPhase != phase {
+			return
+		}
+		if len(g.disconnected) > 0 {
+			log.Printf("Game %s: Declaration phase timed out, waiting for a reconnect.", g.ID)
+			return
+		}
+		log.Printf("Game %s: Declaration phase timed out.", g.ID)
+		g.finishDeclarations()
+	})
+
+	g.broadcastGameState()
+	g.broadcastDeclarationPhase()
+}
+
+// handleDeclaration records a declaration for the end of the phase and acknowledges it
+// privately; nobody else learns of it until the declarations are announced. Assumes lock is held.
+func (g *Game) handleDeclaration(playerIndex int, declaration protocol.DeclarePayload) {
+	player := g.Players[playerIndex]
+	d := declaration.ToDeclaration()
+
+	result := g.Rules.Declare(player, d)
+	if !result.Success {
+		log.Printf("Game %s: Player %s failed to add declaration: %v", g.ID, player.ID, d)
+		g.sendErrorToPlayer(player.ID, "Invalid declaration.")
+		return
+	}
+
+	team := g.teamOf(playerIndex)
+	g.pendingDeclarations[playerIndex] = append(g.pendingDeclarations[playerIndex], protocol.DeclarationConfirmationPayload{
+		TeamID:      team.ID,
+		PlayerID:    player.ID,
+		Points:      result.Points * 3, // Scale points
+		Declaration: declaration,
+		WithoutSuit: result.WithoutSuit,
+	})
+	log.Printf("Game %s: Player %s declared %v, announced when the phase ends.", g.ID, player.ID, d)
+
+	recordedMsg, _ := protocol.NewMessage("declaration_recorded", declaration)
+	g.sendToPlayer(player.ID, recordedMsg)
+	phaseMsg, _ := protocol.NewMessage("declaration_phase", g.declarationPhasePayload(playerIndex))
+	g.sendToPlayer(player.ID, phaseMsg)
+}
+
+// handleDeclarationsDone marks a player as finished declaring and ends the phase
+// when nobody is left. Assumes lock is held.
+func (g *Game) handleDeclarationsDone(playerIndex int) {
+	if g.declarationsDone[playerIndex] {
+		return
+	}
+	g.declarationsDone[playerIndex] = true
+	log.Printf("Game %s: Player %d (%s) has no more declarations.", g.ID, playerIndex, g.Players[playerIndex].Name)
+
+	if len(g.pendingDeclarationIDs()) == 0 {
+		g.finishDeclarations()
+		return
+	}
+	g.broadcastDeclarationPhase()
+}
+
+// finishDeclarations scores and announces the declarations in playing order,
+// starting with the player who leads the first trick, then starts play. Assumes lock is held.
+func (g *Game) finishDeclarations() {
+	seat := g.PlayerTurnIndex
+	for range g.activeSeats() {
+		for _, declaration := range g.pendingDeclarations[seat] {
+			team := g.teamOf(seat)
+			team.AddScore(declaration.Points)
+			g.sheetLine(seat).DeclarationPoints += declaration.Points
+			log.Printf("Game %s: Player %s declared %s. Team %d (ID: %s) score updated to %d.",
+				g.ID, declaration.PlayerID, declaration.Declaration.DeclarationType, team.TeamNumber, team.ID, team.Score)
+
+			g.declarationsMade = append(g.declarationsMade, declaration)
+			g.logEvent(Event{Type: EventDeclaration, Seat: seat, Declaration: &declaration})
+			declarationMsg, _ := protocol.NewMessage("declaration_confirmation", declaration)
+			g.broadcast(declarationMsg)
+		}
+		seat = g.nextActiveSeat(seat)
+	}
+	g.pendingDeclarations = nil
+	g.declarationsDone = nil
+
+	g.beginPlay()
+}
+
+// beginPlay starts the first trick of the round. Assumes lock is held.
+func (g *Game) beginPlay() {
+	g.GameState = Playing
+	log.Printf("Game %s: Round started. Player %d (%s)'s turn.", g.ID, g.PlayerTurnIndex, g.Players[g.PlayerTurnIndex].Name)
+
+	// Notify the starting player it's their turn and broadcast initial state
+	g.startTurnClock()
+	g.broadcastGameState()
+	g.notifyCurrentPlayerTurn()
+}
+
+// pendingDeclarationIDs lists the players who have not finished declaring. Assumes lock is held.
+func (g *Game) pendingDeclarationIDs() []string {
+	ids := []string{}
+	for i, done := range g.declarationsDone {
+		if !done {
+			ids = append(ids, g.Players[i].ID)
+		}
+	}
+	return ids
+}
+
+// declarationPhasePayload describes who still has to declare and, if the player at
+// playerIndex is one of them, what they can declare. Assumes lock is held.
+func (g *Game) declarationPhasePayload(playerIndex int) protocol.DeclarationPhasePayload {
+	payload := protocol.DeclarationPhasePayload{
+		PendingPlayerIDs: g.pendingDeclarationIDs(),
+		SecondsLeft:      int(time.Until(g.declarationDeadline).Seconds()),
+	}
+	if !g.declarationsDone[playerIndex] {
+		player := g.Players[playerIndex]
+		payload.AvailableDeclarations = AvailableDeclarations(g.Rules, player.Hand, player.Declarations)
+	}
+	return payload
+}
+
+// broadcastDeclarationPhase tells everyone who is still declaring. Assumes lock is held.
+func (g *Game) broadcastDeclarationPhase() {
+	for i, player := range g.Players {
+		phaseMsg, _ := protocol.NewMessage("declaration_phase", g.declarationPhasePayload(i))
+		g.sendToPlayer(player.ID, phaseMsg)
+	}
+}
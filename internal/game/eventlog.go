@@ -0,0 +1,109 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"tressette-game/internal/database"
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// EventType names an entry of a game's event log.
+type EventType string
+
+const (
+	EventGameStart   EventType = "game_start"  // Seating, teams and ruleset
+	EventDeal        EventType = "deal"        // Shuffle seed and every hand dealt
+	EventCall        EventType = "call"        // "A chiamare": the card called, or playing alone
+	EventDeclaration EventType = "declaration" // A declaration announced at the end of the phase
+	EventPlay        EventType = "play"        // A card played, with its signal
+	EventDraw        EventType = "draw"        // A card drawn from the stock
+	EventTrickEnd    EventType = "trick_end"   // Who took the trick and its points
+	EventRoundEnd    EventType = "round_end"   // Settled round scores and new totals
+	EventGameOver    EventType = "game_over"   // The winning team
+	EventForfeit     EventType = "forfeit"     // A player left and the game ended
+	EventTimeout     EventType = "timeout"     // A player ran out of time; the card played for them follows
+)
+
+// Event is one state-changing step of a game. Only the fields of its type are set.
+type Event struct {
+	Seq         int                                      `json:"seq"` // Position in the log, starting from 0
+	Type        EventType                                `json:"type"`
+	At          time.Time                                `json:"at"`
+	Round       int                                      `json:"round"`                  // Round the event belongs to, 0 before the first deal
+	Seat        int                                      `json:"seat"`                   // Seat acting (leader of a deal, winner of a trick), -1 if none
+	Start       *protocol.GameStartPayload               `json:"start,omitempty"`        // game_start
+	Seed        *shared.Seed                             `json:"seed,omitempty"`         // deal
+	Hands       [][]shared.Card                          `json:"hands,omitempty"`        // deal, by seat; nil for eliminated seats
+	Morto       *shared.Card                             `json:"morto,omitempty"`        // deal
+	Trump       *shared.Card                             `json:"trump,omitempty"`        // deal
+	Card        *shared.Card                             `json:"card,omitempty"`         // play, draw, call
+	Signal      protocol.Signal                          `json:"signal,omitempty"`       // play
+	Alone       bool                                     `json:"alone,omitempty"`        // call
+	Declaration *protocol.DeclarationConfirmationPayload `json:"declaration,omitempty"`  // declaration
+	Points      int                                      `json:"points,omitempty"`       // trick_end, scaled
+	RoundScores []int                                    `json:"round_scores,omitempty"` // round_end, scaled
+	TotalScores []int                                    `json:"total_scores,omitempty"` // round_end, game_over
+	Eliminated  []int                                    `json:"eliminated,omitempty"`   // round_end, seats knocked out
+	WinningTeam int                                      `json:"winning_team,omitempty"` // game_over, forfeit
+	ScoreSheet  *protocol.RoundScoreSheet                `json:"score_sheet,omitempty"`  // round_end
+}
+
+// logEvent appends an event to the game's log. Assumes lock is held.
+func (g *Game) logEvent(event Event) {
+	event.Seq = len(g.events)
+	event.At = time.Now()
+	event.Round = g.round
+	g.events = append(g.events, event)
+}
+
+// saveEventLog stores the event log of a finished game. Assumes lock is held.
+func (g *Game) saveEventLog() {
+	if g.db == nil {
+		return
+	}
+	records := make([]database.GameEvent, 0, len(g.events))
+	for _, event := range g.events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Game %s: Error encoding event %d: %v", g.ID, event.Seq, err)
+			return
+		}
+		records = append(records, database.GameEvent{
+			GameID: g.ID,
+			Seq:    event.Seq,
+			Type:   string(event.Type),
+			Data:   string(data),
+		})
+	}
+	if err := g.db.InsertEvents(records); err != nil {
+		log.Printf("Game %s: Error saving event log: %v", g.ID, err)
+		return
+	}
+	log.Printf("Game %s: Saved %d events.", g.ID, len(records))
+}
+
+// DecodeEvents turns stored event records back into events.
+func DecodeEvents(records []database.GameEvent) ([]Event, error) {
+	events := make([]Event, len(records))
+	for i, record := range records {
+		if err := json.Unmarshal([]byte(record.Data), &events[i]); err != nil {
+			return nil, fmt.Errorf("event %d: %w", record.Seq, err)
+		}
+	}
+	return events, nil
+}
+
+// copyHands returns a deep copy of hands, so the log doesn't change as cards are played.
+func copyHands(hands [][]shared.Card) [][]shared.Card {
+	copied := make([][]shared.Card, len(hands))
+	for i, hand := range hands {
+		if hand != nil {
+			copied[i] = append([]shared.Card{}, hand...)
+		}
+	}
+	return copied
+}
@@ -21,11 +21,21 @@ const (
 	Dealing        GameState = "Dealing"   // Cards are being dealt
 	Playing        GameState = "Playing"   // Players are playing tricks
 	Declaring      GameState = "Declaring" // Phase for declaring combinations (optional)
-	RoundOver      GameState = "RoundOver" // A round (10 tricks) is finished
+	Calling        GameState = "Calling"   // "A chiamare": the caller is choosing a partner
+	TrickOver      GameState = "TrickOver" // A trick is finished and still shown before the next lead
+	RoundOver      GameState = "RoundOver" // A round (all cards played) is finished
 	GameOver       GameState = "GameOver"  // Target score reached
 	CardsPerPlayer int       = 10          // Number of cards dealt to each player
 )
 
+// Supported table sizes. Heads-up games keep the undealt cards as a stock,
+// three-player games ("Terziglio") set the leftover card aside as the morto.
+const (
+	TwoPlayers   = 2
+	ThreePlayers = 3
+	FourPlayers  = 4
+)
+
 // MessageSender defines the function signature for sending messages back to clients.
 // The Hub will provide an implementation of this.
 type MessageSender func(clientID string, message []byte)
@@ -33,104 +43,103 @@ type MessageSender func(clientID string, message []byte)
 // Game represents the main game state machine.
 type Game struct {
 	ID                   string            `json:"id"`
-	Players              [4]*shared.Player `json:"-"`
-	Teams                [2]*shared.Team   `json:"-"`
+	Players              []*shared.Player  `json:"-"`
+	Teams                []*shared.Team    `json:"-"`
 	Deck                 *shared.Deck      `json:"-"`
 	CurrentTrick         *shared.Trick     `json:"-"`
 	PlayerTurnIndex      int               `json:"player_turn_index"`
 	GameState            GameState         `json:"game_state"`
 	TargetScore          int               `json:"-"`
+	MortoRule            MortoRule         `json:"-"`
+	Mode                 Mode              `json:"mode"` // Taken from the ruleset
+	Rules                Ruleset           `json:"-"`
+	Morto                *shared.Card      `json:"-"` // Card left over in a three-player deal
+	Trump                *shared.Card      `json:"-"` // Card turned up as trump, if the ruleset uses one
 	CardsOnTable         []shared.Card     `json:"cards_on_table"`
 	LedSuit              shared.Suit       `json:"led_suit"`
 	LastTrickWinnerIndex int               `json:"last_trick_winner_index"`
 	LastRoundStartIndex  int               `json:"last_round_start_index"`
 	db                   *database.Service `json:"-"`
 	mu                   sync.Mutex
-	sendMessage          MessageSender `json:"-"`
+	sendMessage          MessageSender                               `json:"-"`
+	disconnected         map[string]bool                             // Players whose seat is held while they reconnect
+	declarationsMade     []protocol.DeclarationConfirmationPayload   // Declarations made in the current round, in order
+	pendingDeclarations  [][]protocol.DeclarationConfirmationPayload // Declarations per seat, announced when the phase ends
+	declarationsDone     []bool                                      // Seats that have finished declaring
+	declarationPhase     int                                         // Counts declaration phases so a stale timer is ignored
+	declarationDeadline  time.Time                                   // When the current declaration phase times out
+	declarationTimeout   time.Duration                               // Length of the declaration phase
+	agents               map[string]Agent                            // Seats controlled by agents instead of clients
+	eliminated           []bool                                      // Seats knocked out of an "a perdere" game
+	callerIndex          int                                         // "A chiamare": seat that calls this round
+	calledCard           *shared.Card                                // Card called this round, nil if playing alone
+	playsAlone           bool                                        // Caller plays alone with all four 3s
+	partnerIndex         int                                         // Seat holding the called card, -1 if none
+	partnerRevealed      bool                                        // Whether the called card has been played
+	spectators           map[string]bool                             // Clients watching the game; they never get deal_hand
+	spectatorHandDelay   time.Duration                               // How long before spectators see the hands, zero keeps them hidden
+	handsRevealAt        int                                         // Length of the event log when hands were last queued for spectators, -1 if never
+	signalsAllowed       bool                                        // Whether busso, striscio and volo may be used
+	leadSignal           protocol.Signal                             // Signal given with the card leading the current trick
+	leadSignalSeat       int                                         // Seat that gave leadSignal
+	signalHistory        []protocol.SignalRecord                     // Every signal given in the game
+	round                int                                         // Current round, starting from 1
+	tricksPlayed         int                                         // Tricks completed in the current round
+	events               []Event                                     // Every state-changing event, in order
+	seed                 shared.Seed                                 // Shuffle seed of the current round, revealed at round_end
+	turnTimeout          time.Duration                               // Time allowed per move before the bank is used, zero for no limit
+	timeBank             time.Duration                               // Starting time bank of every player, zero for none
+	timeBanks            []time.Duration                             // Time bank left per seat
+	turnClock            int                                         // Counts started clocks so a stale timer is ignored
+	turnStart            time.Time                                   // When the current player's clock started
+	turnDeadline         time.Time                                   // When the current player runs out of time, zero if no clock is running
+	timeouts             []int                                       // How often each seat ran out of time
+	trickPause           time.Duration                               // How long a finished trick stays on the table
+	readyTimeout         time.Duration                               // How long to wait for everyone to be ready after a round, zero to go on at once
+	ready                []bool                                      // Seats ready for the next round, nil outside a ready check
+	readyDeadline        time.Time                                   // When the current ready check times out
+	pause                int                                         // Counts trick pauses and ready checks so a stale timer is ignored
+	roundSheet           []protocol.TeamRoundScore                   // Score sheet of the round in progress, by team
+	scoreSheet           []protocol.RoundScoreSheet                  // Score sheet of every finished round
+	rounding             RoundingPolicy                              // How leftover thirds are handled
+	cappottoBonus        int                                         // Extra points for a cappotto, zero for none
+	excludeDeclarations  bool                                        // Whether declarations are left out when checking the points goal
+	ranked               bool                                        // Whether the result moves the players' ratings
+	carriedThirds        []int                                       // Leftover thirds each team carries into the next round
+	onGameOver           func(Outcome)                               // Called once the game is over, nil if nobody asked
 }
 
-// NewGame initializes a new game instance.
-func NewGame(players [4]*shared.Player, targetScore int, db *database.Service) *Game {
-	var teams [2]*shared.Team
-	var newPlayers [4]*shared.Player
-	var first, second, third, fourth *shared.Player
-	if players[0].DesiredTeam == shared.TeamRed {
-		first = players[0]
-	} else {
-		second = players[0]
-	}
-	if players[1].DesiredTeam == shared.TeamRed {
-		if players[0].DesiredTeam == shared.TeamRed {
-			third = players[1]
-		} else {
-			first = players[1]
-		}
-	} else {
-		if players[0].DesiredTeam == shared.TeamRed {
-			second = players[1]
-		} else {
-			fourth = players[1]
-		}
+// NewGame initializes a new game instance for two, three or four players.
+func NewGame(players []*shared.Player, settings Settings, db *database.Service) *Game {
+	rules := settings.Rules
+	if rules == nil {
+		rules, _ = LookupRuleset(DefaultRuleset)
 	}
-	if players[2].DesiredTeam == shared.TeamRed {
-		if players[0].DesiredTeam == shared.TeamRed {
-			if players[1].DesiredTeam == shared.TeamRed {
-				second = players[2]
-			} else {
-				third = players[2]
-			}
-		} else {
-			if players[1].DesiredTeam == shared.TeamRed {
-				third = players[2]
-			} else {
-				first = players[2]
-			}
-		}
-	} else {
-		if players[0].DesiredTeam == shared.TeamRed {
-			if players[1].DesiredTeam == shared.TeamRed {
-				second = players[2]
-			} else {
-				fourth = players[2]
-			}
-		} else {
-			if players[1].DesiredTeam == shared.TeamRed {
-				fourth = players[2]
-			} else {
-				first = players[2]
-			}
-		}
+
+	// Players sit in the order given, so the game starts with the seating chosen in the lobby
+	newPlayers := players
+	teams := make([]*shared.Team, TeamCount(len(players), rules.Mode()))
+	for i := range teams {
+		teams[i] = shared.NewTeam(i + 1)
 	}
-	var red_count, blue_count int
-	if players[0].DesiredTeam == shared.TeamRed {
-		red_count++
-	} else {
-		blue_count++
+	for seat, p := range players {
+		team := teams[SeatTeam(seat, len(players), rules.Mode())-1]
+		team.Players = append(team.Players, p)
 	}
-	if players[1].DesiredTeam == shared.TeamRed {
-		red_count++
-	} else {
-		blue_count++
+	gameID := uuid.New().String()
+	timeBanks := make([]time.Duration, len(newPlayers))
+	for i := range timeBanks {
+		timeBanks[i] = settings.TimeBank
 	}
-	if players[2].DesiredTeam == shared.TeamRed {
-		red_count++
-	} else {
-		blue_count++
+	rounding := settings.Rounding
+	if rounding == "" {
+		rounding = RoundTruncate
 	}
-	if red_count > blue_count {
-		fourth = players[3]
-	} else {
-		third = players[3]
+	declarationTimeout := settings.DeclarationTimeout
+	if declarationTimeout <= 0 {
+		declarationTimeout = DefaultDeclarationTimeout
 	}
 
-	teams[0] = shared.NewTeam(1, first, third)   // Team 1 (Red)
-	teams[1] = shared.NewTeam(2, second, fourth) // Team 2 (Blue)
-	newPlayers[0] = first
-	newPlayers[1] = second
-	newPlayers[2] = third
-	newPlayers[3] = fourth
-	gameID := uuid.New().String()
-
 	return &Game{
 		ID:                   gameID,
 		Players:              newPlayers,
@@ -139,15 +148,53 @@ func NewGame(players [4]*shared.Player, targetScore int, db *database.Service) *
 		CurrentTrick:         shared.NewTrick(),
 		PlayerTurnIndex:      0,
 		GameState:            Dealing, // Initial state is Dealing
-		TargetScore:          targetScore,
+		TargetScore:          rules.TargetScore(settings.TargetScore),
+		MortoRule:            settings.MortoRule,
+		Mode:                 rules.Mode(),
+		Rules:                rules,
 		CardsOnTable:         []shared.Card{},
 		LedSuit:              "",
 		LastTrickWinnerIndex: -1,
 		LastRoundStartIndex:  0,
 		db:                   db,
+		disconnected:         make(map[string]bool),
+		agents:               make(map[string]Agent),
+		eliminated:           make([]bool, len(newPlayers)),
+		partnerIndex:         -1,
+		declarationTimeout:   declarationTimeout,
+		spectators:           make(map[string]bool),
+		spectatorHandDelay:   settings.SpectatorHandDelay,
+		handsRevealAt:        -1,
+		signalsAllowed:       !settings.Silent,
+		turnTimeout:          settings.TurnTimeout,
+		timeBank:             settings.TimeBank,
+		timeBanks:            timeBanks,
+		timeouts:             make([]int, len(newPlayers)),
+		trickPause:           settings.TrickPause,
+		readyTimeout:         settings.ReadyTimeout,
+		rounding:             rounding,
+		cappottoBonus:        settings.CappottoBonus,
+		excludeDeclarations:  settings.ExcludeDeclarations,
+		ranked:               settings.Ranked,
+		carriedThirds:        make([]int, len(teams)),
 	}
 }
 
+// TeamCount returns how many teams a table of the given size has.
+func TeamCount(players int, mode Mode) int {
+	if players == FourPlayers && mode == ModeClassic {
+		return 2
+	}
+	// Two or three players, "a perdere" or "a chiamare": everyone is scored as a team of one
+	return players
+}
+
+// SeatTeam returns the number of the team the given seat plays for. Teams
+// alternate around the table, so partners sit opposite each other.
+func SeatTeam(seat, players int, mode Mode) int {
+	return seat%TeamCount(players, mode) + 1
+}
+
 // StartGameLoop initializes the game and runs the first round.
 // It's called in a goroutine by the Hub.
 func (g *Game) StartGameLoop(sender MessageSender) {
@@ -156,35 +203,50 @@ func (g *Game) StartGameLoop(sender MessageSender) {
 	log.Printf("Game %s: Starting game loop.", g.ID)
 
 	// 1. Send Game Start message to all players
-	playerInfos := make([]protocol.PlayerInfo, 4)
+	startPayload := g.gameStartPayload()
+	startMsg, _ := protocol.NewMessage("game_start", startPayload)
+	g.broadcast(startMsg)
+	g.logEvent(Event{Type: EventGameStart, Seat: -1, Start: &startPayload})
+
+	// 2. Start the first round
+	g.startRound() // This will deal cards and send initial turn messages
+	g.mu.Unlock()  // Unlock after initial setup
+}
+
+// gameStartPayload describes the seating and teams. Assumes lock is held.
+func (g *Game) gameStartPayload() protocol.GameStartPayload {
+	playerInfos := make([]protocol.PlayerInfo, len(g.Players))
 	for i, p := range g.Players {
 		playerInfos[i] = protocol.PlayerInfo{ID: p.ID, Name: p.Name, Position: i}
 	}
-	teamInfos := make([]protocol.TeamInfo, 2)
+	teamInfos := make([]protocol.TeamInfo, len(g.Teams))
 	for i, t := range g.Teams {
+		members := []protocol.PlayerInfo{}
+		for _, p := range t.Players {
+			members = append(members, protocol.PlayerInfo{ID: p.ID, Name: p.Name, Position: g.GetPlayerIndex(p.ID)})
+		}
 		teamInfos[i] = protocol.TeamInfo{
-			ID: t.ID,
-			Players: []protocol.PlayerInfo{
-				{ID: t.Players[0].ID, Name: t.Players[0].Name, Position: i * 2},
-				{ID: t.Players[1].ID, Name: t.Players[1].Name, Position: i*2 + 1},
-			},
+			ID:         t.ID,
+			Players:    members,
 			Score:      t.Score,
 			TeamNumber: t.TeamNumber,
 		}
 	}
 
-	startPayload := protocol.GameStartPayload{
-		GameID:     g.ID,
-		Players:    playerInfos,
-		Teams:      teamInfos,
-		PointsGoal: g.TargetScore,
+	return protocol.GameStartPayload{
+		GameID:              g.ID,
+		Players:             playerInfos,
+		Teams:               teamInfos,
+		PointsGoal:          g.TargetScore,
+		Mode:                string(g.Mode),
+		Ruleset:             g.Rules.Name(),
+		Declarations:        g.Rules.AllowsDeclarations(),
+		Signals:             g.signalsAllowed,
+		Rounding:            string(g.rounding),
+		CappottoBonus:       g.cappottoBonus,
+		ExcludeDeclarations: g.excludeDeclarations,
+		Ranked:              g.ranked,
 	}
-	startMsg, _ := protocol.NewMessage("game_start", startPayload)
-	g.broadcast(startMsg)
-
-	// 2. Start the first round
-	g.startRound() // This will deal cards and send initial turn messages
-	g.mu.Unlock()  // Unlock after initial setup
 }
 
 // startRound begins a new round (shuffling, dealing, setting state).
@@ -201,11 +263,14 @@ func (g *Game) startRound() {
 	for _, team := range g.Teams {
 		team.ResetScore()
 	}
-	g.Deck = shared.NewDeck()
-	g.Deck.Shuffle()
 	g.CardsOnTable = []shared.Card{}
 	g.CurrentTrick = shared.NewTrick()
 	g.LedSuit = ""
+	g.declarationsMade = nil
+	g.round++
+	g.tricksPlayed = 0
+	g.leadSignal = ""
+	g.newRoundSheet()
 
 	// Determine who starts based on the last trick winner or the last round start index
 	if g.LastTrickWinnerIndex != -1 {
@@ -214,19 +279,35 @@ func (g *Game) startRound() {
 		g.PlayerTurnIndex = g.LastRoundStartIndex
 	}
 
-	// Deal 10 cards to each player
-	hands := g.Deck.Deal(len(g.Players), CardsPerPlayer)
+	// Commit to the shuffle before dealing; the seed itself is revealed at round_end
+	seed, err := shared.NewSeed()
+	if err != nil {
+		log.Printf("Game %s: Error drawing a shuffle seed: %v", g.ID, err)
+		g.GameState = GameOver
+		g.broadcastError("Internal server error during shuffling.")
+		return
+	}
+	g.seed = seed
+	commitmentPayload := protocol.ShuffleCommitmentPayload{Round: g.round, Commitment: seed.Commitment()}
+	commitmentMsg, _ := protocol.NewMessage("shuffle_commitment", commitmentPayload)
+	g.broadcast(commitmentMsg)
+
+	hands := g.deal(seed)
 	if hands == nil {
 		log.Printf("Error dealing cards in game %s", g.ID)
 		g.GameState = GameOver
 		g.broadcastError("Internal server error during dealing.")
 		return
 	}
-	for i, hand := range hands {
+	g.logEvent(Event{Type: EventDeal, Seat: g.PlayerTurnIndex, Seed: &seed, Hands: copyHands(hands), Morto: g.Morto, Trump: g.Trump})
+
+	for _, i := range g.activeSeats() {
+		hand := hands[i]
 		if g.Players[i] != nil {
 			g.Players[i].Hand = hand
+			g.Players[i].Declarations = []shared.Declaration{}
 			// Send hand to the specific player
-			dealPayload := protocol.DealHandPayload{Hand: hand}
+			dealPayload := protocol.DealHandPayload{Hand: hand, Trump: g.Trump}
 			dealMsg, _ := protocol.NewMessage("deal_hand", dealPayload)
 			g.sendToPlayer(g.Players[i].ID, dealMsg)
 		} else {
@@ -237,12 +318,46 @@ func (g *Game) startRound() {
 		}
 	}
 
-	g.GameState = Playing
-	log.Printf("Game %s: Round started. Player %d (%s)'s turn.", g.ID, g.PlayerTurnIndex, g.Players[g.PlayerTurnIndex].Name)
+	if g.Mode == ModeChiamare {
+		g.startCall()
+		return
+	}
+	g.startDeclarations()
+}
 
-	// Notify the starting player it's their turn and broadcast initial state
-	g.broadcastGameState()
-	g.notifyCurrentPlayerTurn()
+// deal shuffles a new deck with the given seed and deals a hand to everyone still
+// in, setting aside the morto and turning up the trump as the table requires; in
+// heads-up games the rest stays in the stock. Returns the hands by seat (nil for
+// eliminated seats), or nil if the deck ran short. Assumes lock is held.
+func (g *Game) deal(seed shared.Seed) [][]shared.Card {
+	g.Deck = shared.NewDeck()
+	g.Deck.Shuffle(seed)
+
+	seats := g.activeSeats()
+	dealt := g.Deck.Deal(len(seats), g.Rules.HandSize(len(seats)))
+	if dealt == nil {
+		return nil
+	}
+	hands := make([][]shared.Card, len(g.Players))
+	for j, hand := range dealt {
+		hands[seats[j]] = hand
+	}
+
+	g.Morto = nil
+	if len(seats) == ThreePlayers {
+		if morto, ok := g.Deck.Draw(); ok {
+			g.Morto = &morto
+			log.Printf("Game %s: Morto set aside (%s).", g.ID, g.MortoRule)
+		}
+	}
+	g.Trump = nil
+	if g.Rules.HasTrump() {
+		if trump, ok := g.Deck.TurnUp(); ok {
+			g.Trump = &trump
+			log.Printf("Game %s: Trump card is %s %s.", g.ID, trump.Rank, trump.Suit)
+		}
+	}
+	return hands
 }
 
 // HandlePlayerAction processes incoming actions from a player.
@@ -263,6 +378,12 @@ func (g *Game) HandlePlayerAction(clientID string, msg protocol.Message) {
 		return // Don't send error to potentially invalid client
 	}
 
+	if len(g.disconnected) > 0 {
+		log.Printf("Game %s: Action from %s ignored, game is paused for a reconnect.", g.ID, clientID)
+		g.sendErrorToPlayer(clientID, "Game is paused while a player reconnects.")
+		return
+	}
+
 	switch msg.Type {
 	case "play_card":
 		if g.GameState != Playing {
@@ -291,45 +412,72 @@ func (g *Game) HandlePlayerAction(clientID string, msg protocol.Message) {
 			return
 		}
 
+		if reason := g.checkSignal(g.Players[playerIndex].Hand, *cardToPlay, payload.Signal); reason != "" {
+			log.Printf("Game %s: Player %s sent invalid signal %q: %s", g.ID, clientID, payload.Signal, reason)
+			g.sendErrorToPlayer(clientID, reason)
+			return
+		}
+
 		// Validate and process the play
-		if !g.playCard(playerIndex, *cardToPlay) {
+		if !g.playCard(playerIndex, *cardToPlay, payload.Signal) {
 			g.sendErrorToPlayer(clientID, "Invalid move.")
 		}
 
 	case "declare":
-		if g.GameState != Playing {
+		if !g.Rules.AllowsDeclarations() {
+			g.sendErrorToPlayer(clientID, "Declarations are not used in this game.")
+			return
+		}
+
+		// Declarations are only allowed during the declaration phase, until the player is done
+		if g.GameState != Declaring || g.declarationsDone[playerIndex] {
 			log.Printf("Game %s: Received declare from %s in wrong state %s", g.ID, clientID, g.GameState)
 			g.sendErrorToPlayer(clientID, "Cannot declare now.")
 			return
 		}
 
-		if playerIndex != g.PlayerTurnIndex {
-			log.Printf("Game %s: Received declare from %s out of turn (current: %d)", g.ID, clientID, g.PlayerTurnIndex)
-			g.sendErrorToPlayer(clientID, "Not your turn.")
+		var payload protocol.DeclarePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Game %s: Error unmarshalling declare payload from %s: %v", g.ID, clientID, err)
+			g.sendErrorToPlayer(clientID, "Invalid declare message.")
 			return
 		}
 
-		// Check if the player had not yet played a card in this game
-		// He must have as much cards in his hand as the number that were dealt
-		for _, player := range g.Players {
-			if player != nil && player.ID == clientID {
-				if len(player.Hand) != CardsPerPlayer {
-					log.Printf("Game %s: Player %s tried to declare but has %d cards in hand.", g.ID, clientID, len(player.Hand))
-					g.sendErrorToPlayer(clientID, "Invalid declaration: must have the same number of cards as dealt.")
-					return
-				}
-				break
-			}
+		g.handleDeclaration(playerIndex, payload)
+
+	case "declarations_done":
+		if g.GameState != Declaring {
+			log.Printf("Game %s: Received declarations_done from %s in wrong state %s", g.ID, clientID, g.GameState)
+			g.sendErrorToPlayer(clientID, "Not in the declaration phase.")
+			return
 		}
 
-		var payload protocol.DeclarePayload
+		g.handleDeclarationsDone(playerIndex)
+
+	case "call_card":
+		if g.GameState != Calling || playerIndex != g.callerIndex {
+			log.Printf("Game %s: Received call_card from %s in state %s", g.ID, clientID, g.GameState)
+			g.sendErrorToPlayer(clientID, "Cannot call a card now.")
+			return
+		}
+
+		var payload protocol.CallCardPayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-			log.Printf("Game %s: Error unmarshalling declare payload from %s: %v", g.ID, clientID, err)
-			g.sendErrorToPlayer(clientID, "Invalid declare message.")
+			log.Printf("Game %s: Error unmarshalling call_card payload from %s: %v", g.ID, clientID, err)
+			g.sendErrorToPlayer(clientID, "Invalid call_card message.")
 			return
 		}
 
-		g.handleDeclaration(clientID, payload)
+		g.handleCall(playerIndex, payload)
+
+	case "ready":
+		if g.GameState != RoundOver || g.ready == nil {
+			log.Printf("Game %s: Received ready from %s in state %s", g.ID, clientID, g.GameState)
+			g.sendErrorToPlayer(clientID, "Nothing to get ready for.")
+			return
+		}
+
+		g.handleReady(playerIndex)
 
 	default:
 		log.Printf("Game %s: Received unhandled action type '%s' from %s", g.ID, msg.Type, clientID)
@@ -338,7 +486,7 @@ func (g *Game) HandlePlayerAction(clientID string, msg protocol.Message) {
 
 // playCard handles the logic of playing a card, updating state, and notifying clients.
 // Assumes lock is held. Returns true if successful, false otherwise.
-func (g *Game) playCard(playerIndex int, card shared.Card) bool {
+func (g *Game) playCard(playerIndex int, card shared.Card, signal protocol.Signal) bool {
 	player := g.Players[playerIndex]
 
 	// Validate the move
@@ -355,25 +503,30 @@ func (g *Game) playCard(playerIndex int, card shared.Card) bool {
 		g.GameState = GameOver
 		log.Panicf("Game %s: Game over due to hand inconsistency.", g.ID)
 	}
+	g.stopTurnClock()
 
 	// Add card to trick and table
 	if len(g.CurrentTrick.Cards) == 0 {
 		g.LedSuit = card.Suit
+		g.recordSignal(playerIndex, card, signal)
 	}
 	g.CurrentTrick.AddCard(card, playerIndex)
 	g.CardsOnTable = append(g.CardsOnTable, card) // Keep track for state updates
+	g.logEvent(Event{Type: EventPlay, Seat: playerIndex, Card: &card, Signal: signal})
 	log.Printf("Game %s: Player %d (%s) played %s %s", g.ID, playerIndex, player.Name, card.Rank, card.Suit)
 
 	g.notifyPlayerPlayedCard(player.ID, card) // Notify player of their action
+	g.revealPartnerIfCalled(card)
 
 	// Check if trick is complete
-	if len(g.CurrentTrick.Cards) == len(g.Players) {
+	if len(g.CurrentTrick.Cards) == g.activeCount() {
 		g.broadcastGameState()
 		defer g.endTrick() // Handles scoring, next turn/round logic
 	} else {
 		// Advance turn to the next player
-		g.PlayerTurnIndex = (g.PlayerTurnIndex + 1) % len(g.Players)
+		g.PlayerTurnIndex = g.nextActiveSeat(g.PlayerTurnIndex)
 		log.Printf("Game %s: Turn advanced to player %d (%s)", g.ID, g.PlayerTurnIndex, g.Players[g.PlayerTurnIndex].Name)
+		g.startTurnClock()
 		g.broadcastGameState()
 		defer g.notifyCurrentPlayerTurn()
 	}
@@ -383,19 +536,25 @@ func (g *Game) playCard(playerIndex int, card shared.Card) bool {
 
 // isValidPlay checks if playing a card is legal. Assumes lock is held.
 func (g *Game) isValidPlay(player *shared.Player, card shared.Card) bool {
-	if len(g.CurrentTrick.Cards) == 0 {
-		return true // Can lead with any card
+	return g.Rules.IsValidPlay(player.Hand, card, g.trickState())
+}
+
+// trickState describes the trick in progress for the ruleset. Assumes lock is held.
+func (g *Game) trickState() TrickState {
+	state := TrickState{StockRemaining: len(g.Deck.Cards)}
+	if g.Trump != nil {
+		state.Trump = g.Trump.Suit
 	}
-	if player.HasSuit(g.LedSuit) {
-		return card.Suit == g.LedSuit // Must follow suit if possible
+	if len(g.CurrentTrick.Cards) > 0 {
+		state.LedSuit = g.LedSuit
 	}
-	return true // Can play any card if unable to follow suit
+	return state
 }
 
 // endTrick concludes the current trick. Assumes lock is held.
 func (g *Game) endTrick() {
 	log.Printf("Game %s: Ending trick...", g.ID)
-	card := g.CurrentTrick.DetermineWinner(g.LedSuit)
+	card := g.Rules.TrickWinner(g.CurrentTrick, g.trickState())
 	if card.PlayerIndex == -1 {
 		log.Panicf("Game %s: Error determining trick winner. No valid winner found.", g.ID)
 		return
@@ -403,9 +562,7 @@ func (g *Game) endTrick() {
 
 	g.LastTrickWinnerIndex = card.PlayerIndex
 	winningPlayer := g.Players[card.PlayerIndex]
-	// Find the winning team based on the player index (0 or 2 -> team 0, 1 or 3 -> team 1)
-	// is this good?
-	winningTeam := g.Teams[card.PlayerIndex%2]
+	winningTeam := g.teamOf(card.PlayerIndex)
 
 	trickCardsForScoring := []shared.Card{}
 	trickCardInfos := make([]shared.Card, len(g.CurrentTrick.Cards)) // For broadcast
@@ -413,15 +570,25 @@ func (g *Game) endTrick() {
 		trickCardsForScoring = append(trickCardsForScoring, pc.Card)
 		trickCardInfos[i] = pc.Card
 	}
-	trickPoints := g.calculateTrickPoints(trickCardsForScoring)
+	cardPoints := g.Rules.TrickPoints(trickCardsForScoring)
+	trickPoints := cardPoints
 
-	isLastTrick := len(g.Players[0].Hand) == 0
+	isLastTrick := len(winningPlayer.Hand) == 0 && len(g.Deck.Cards) == 0
+	bonus, mortoPoints := 0, 0
 	if isLastTrick {
-		trickPoints += 3 // Scaled bonus point for last trick
-		log.Printf("Game %s: Last trick bonus point (scaled: 3) awarded.", g.ID)
+		bonus = g.Rules.LastTrickBonus()
+		trickPoints += bonus
+		log.Printf("Game %s: Last trick bonus (scaled: %d) awarded.", g.ID, bonus)
+		if g.Morto != nil && g.MortoRule == MortoLastTrick {
+			mortoPoints = g.Morto.Value
+			trickPoints += mortoPoints
+			log.Printf("Game %s: Morto %s %s (scaled: %d) goes to the last trick.", g.ID, g.Morto.Rank, g.Morto.Suit, g.Morto.Value)
+		}
 	}
+	g.tallyTrick(card.PlayerIndex, trickCardsForScoring, cardPoints, bonus, mortoPoints)
 
 	winningTeam.AddScore(trickPoints)
+	g.logEvent(Event{Type: EventTrickEnd, Seat: card.PlayerIndex, Points: trickPoints})
 	log.Printf("Game %s: Trick won by Player %d (%s). Scaled Points: %d. Team %d",
 		g.ID, card.PlayerIndex, winningPlayer.Name, trickPoints, winningTeam.TeamNumber)
 
@@ -439,26 +606,57 @@ func (g *Game) endTrick() {
 	g.CardsOnTable = []shared.Card{}
 	g.CurrentTrick = shared.NewTrick()
 	g.LedSuit = ""
+	g.leadSignal = ""
+	g.tricksPlayed++
 	g.PlayerTurnIndex = card.PlayerIndex // Winner leads next
+	g.drawFromStock(card.PlayerIndex)
 
 	// Check if the round is over
 	if isLastTrick {
 		g.endRound()
 	} else {
-		log.Printf("Game %s: Next trick. Player %d (%s)'s turn.", g.ID, g.PlayerTurnIndex, g.Players[g.PlayerTurnIndex].Name)
-		// Broadcast state update (shows empty table) and notify next player
-		g.broadcastGameState()
-		g.notifyCurrentPlayerTurn()
+		g.pauseAfterTrick()
 	}
 }
 
-// calculateTrickPoints calculates scaled points. Assumes lock is held.
-func (g *Game) calculateTrickPoints(trickCards []shared.Card) int {
-	scaledPoints := 0
-	for _, card := range trickCards {
-		scaledPoints += card.Value // Values are already scaled
+// drawFromStock gives every player one card from the stock, starting with the trick winner.
+// Drawn cards are shown to everyone unless the ruleset keeps them private. Assumes lock is held.
+func (g *Game) drawFromStock(winnerIndex int) {
+	if len(g.Deck.Cards) == 0 {
+		return
+	}
+	seat := winnerIndex
+	for i := 0; i < g.activeCount(); i++ {
+		player := g.Players[seat]
+		seat = g.nextActiveSeat(seat)
+		card, ok := g.Deck.Draw()
+		if !ok {
+			log.Printf("Game %s: Stock ran out while drawing for player %s.", g.ID, player.Name)
+			return
+		}
+		player.AddCard(card)
+		g.logEvent(Event{Type: EventDraw, Seat: g.GetPlayerIndex(player.ID), Card: &card})
+		log.Printf("Game %s: Player %s drew %s %s. %d cards left in stock.", g.ID, player.Name, card.Rank, card.Suit, len(g.Deck.Cards))
+
+		drawnPayload := protocol.CardDrawnPayload{
+			PlayerID:       player.ID,
+			Card:           &card,
+			StockRemaining: len(g.Deck.Cards),
+		}
+		drawnMsg, _ := protocol.NewMessage("card_drawn", drawnPayload)
+		if g.Rules.RevealsDraws() {
+			g.broadcast(drawnMsg)
+			continue
+		}
+		g.sendToPlayer(player.ID, drawnMsg)
+		drawnPayload.Card = nil
+		hiddenMsg, _ := protocol.NewMessage("card_drawn", drawnPayload)
+		for _, other := range g.Players {
+			if other.ID != player.ID {
+				g.sendToPlayer(other.ID, hiddenMsg)
+			}
+		}
 	}
-	return scaledPoints
 }
 
 // endRound finalizes the round. Assumes lock is held.
@@ -468,6 +666,27 @@ func (g *Game) endRound() {
 	// This function should save the score to the database and reset the round
 	log.Printf("Game %s: Round ended.", g.ID)
 	g.GameState = RoundOver
+	rawScores := g.roundScores()
+
+	cappottoPlayerID := ""
+	switch g.Mode {
+	case ModeMisere:
+		if seat := g.applyCappotto(); seat != -1 {
+			cappottoPlayerID = g.Players[seat].ID
+		}
+	case ModeChiamare:
+		g.settlePartnerships()
+	}
+	for i, score := range g.Rules.SettleRound(g.roundScores()) {
+		g.Teams[i].ResetScore()
+		g.Teams[i].AddScore(score)
+	}
+	ruleScores := g.roundScores()
+	g.applyCappottoBonus()
+	g.carryThirdsIn()
+	settledScores := g.roundScores()
+	g.roundThirds()
+	roundPoints := g.roundScores()
 
 	// Update total scores
 	for _, team := range g.Teams {
@@ -476,85 +695,224 @@ func (g *Game) endRound() {
 			g.ID, team.TeamNumber, team.ID, team.TotalScore, team.Score)
 	}
 
-	// Broadcast round end info
+	sheet := g.closeRoundSheet(rawScores, ruleScores, roundPoints)
+
+	// Broadcast round end info; the round scores are taken before TransferScore reset them
 	roundEndPayload := protocol.RoundEndPayload{
-		Team1RoundScore: g.Teams[0].Score,
-		Team2RoundScore: g.Teams[1].Score,
-		Team1TotalScore: g.Teams[0].TotalScore,
-		Team2TotalScore: g.Teams[1].TotalScore,
+		Team1RoundScore:  settledScores[0],
+		Team2RoundScore:  settledScores[1],
+		Team1TotalScore:  g.Teams[0].TotalScore,
+		Team2TotalScore:  g.Teams[1].TotalScore,
+		RoundScores:      settledScores,
+		TotalScores:      g.totalScores(),
+		Morto:            g.Morto,
+		CappottoPlayerID: cappottoPlayerID,
+		Seed:             g.seed.String(),
+		Commitment:       g.seed.Commitment(),
+		ScoreSheet:       sheet,
 	}
 	roundEndMsg, _ := protocol.NewMessage("round_end", roundEndPayload)
 	g.broadcast(roundEndMsg)
 
-	// Check for game over
+	// Check for game over; when playing a perdere only the players still in can win
 	gameOver := false
-	var winningTeam *shared.Team
-	if g.Teams[0].TotalScore != g.Teams[1].TotalScore && (g.Teams[0].TotalScore >= g.TargetScore || g.Teams[1].TotalScore >= g.TargetScore) {
-		var team *shared.Team
-		if g.Teams[0].TotalScore > g.Teams[1].TotalScore {
-			team = g.Teams[0]
-		} else {
-			team = g.Teams[1]
+	contenders := g.Teams
+	var eliminated []int
+	if g.Mode == ModeMisere {
+		eliminated = g.eliminatePlayers()
+		contenders = nil
+		for _, seat := range g.activeSeats() {
+			contenders = append(contenders, g.teamOf(seat))
 		}
+	}
+	g.logEvent(Event{Type: EventRoundEnd, Seat: -1, RoundScores: settledScores, TotalScores: g.totalScores(), Eliminated: eliminated, ScoreSheet: &sheet})
+	totals := g.targetTotals(contenders)
+	var winningTeam *shared.Team
+	winner := g.Rules.Winner(totals, g.TargetScore)
+	if winner == -1 && g.Mode == ModeChiamare {
+		winner = g.chiamareTiebreak(totals, g.TargetScore)
+	}
+	if winner != -1 {
+		winningTeam = contenders[winner]
+	}
+	if winningTeam != nil {
 		g.GameState = GameOver
 		gameOver = true
-		winningTeam = team
-		log.Printf("Game %s: Game Over! Team %d (ID: %s) wins.", g.ID, team.TeamNumber, team.ID)
-		now := time.Now()
-		g.db.Insert(database.GameResult{
-			ID:          g.ID,
-			Team1Score:  g.Teams[0].TotalScore,
-			Team2Score:  g.Teams[1].TotalScore,
-			Player1:     g.Teams[0].Players[0].Name,
-			Player2:     g.Teams[0].Players[1].Name,
-			Player3:     g.Teams[1].Players[0].Name,
-			Player4:     g.Teams[1].Players[1].Name,
-			Player1Team: g.Teams[0].TeamNumber,
-			Player2Team: g.Teams[0].TeamNumber,
-			Player3Team: g.Teams[1].TeamNumber,
-			Player4Team: g.Teams[1].TeamNumber,
-			CreatedAt:   now.Format(time.RFC3339),
-		})
-
-		// Broadcast game over
-		gameOverPayload := protocol.GameOverPayload{
-			WinningTeamID: winningTeam.ID,
-			FinalScoreT1:  g.Teams[0].TotalScore,
-			FinalScoreT2:  g.Teams[1].TotalScore,
+		log.Printf("Game %s: Game Over! Team %d (ID: %s) wins.", g.ID, winningTeam.TeamNumber, winningTeam.ID)
+		if g.saveResult(-1) {
+			g.updateRatings(winningTeam, -1)
 		}
-		gameOverMsg, _ := protocol.NewMessage("game_over", gameOverPayload)
-		g.broadcast(gameOverMsg)
+		g.logEvent(Event{Type: EventGameOver, Seat: -1, TotalScores: g.totalScores(), WinningTeam: winningTeam.TeamNumber})
+		g.saveEventLog()
 
+		// Broadcast game over
+		g.broadcastGameOver(winningTeam)
+		g.reportOutcome(winningTeam, "")
 	}
 	if !gameOver {
 		log.Printf("Game %s: Preparing for next round.", g.ID)
 		g.LastTrickWinnerIndex = -1
-		g.LastRoundStartIndex = (g.LastRoundStartIndex + 1) % 4
-		g.startRound()
+		g.LastRoundStartIndex = g.nextActiveSeat(g.LastRoundStartIndex)
+		g.startReadyCheck()
 	} else {
 		log.Printf("Game %s: Final state reached. Winning Team: %d (ID: %s)", g.ID, winningTeam.TeamNumber, winningTeam.ID)
 	}
 }
 
-// HandlePlayerDisconnect handles a player leaving mid-game.
-func (g *Game) HandlePlayerDisconnect(clientID string) {
+// HandlePlayerDisconnect pauses the game while a disconnected player's seat is held.
+// Returns false if there is nothing to hold (game over or unknown player).
+func (g *Game) HandlePlayerDisconnect(clientID string, gracePeriod time.Duration) bool {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	if g.GameState == GameOver {
 		log.Printf("Game %s: Player %s disconnected, but game already over.", g.ID, clientID)
-		return // Game already over
+		return false
 	}
 
 	playerIndex := g.GetPlayerIndex(clientID)
 	if playerIndex == -1 {
 		log.Printf("Game %s: Disconnect from unknown or already removed client ID %s", g.ID, clientID)
+		return false
+	}
+	if g.eliminated[playerIndex] {
+		log.Printf("Game %s: Eliminated player %s disconnected, nothing to hold.", g.ID, clientID)
+		return false
+	}
+
+	g.disconnected[clientID] = true
+	g.stopTurnClock()
+	log.Printf("Game %s: Player %s (%s) disconnected. Holding seat for %s.", g.ID, clientID, g.Players[playerIndex].Name, gracePeriod)
+
+	payload := protocol.PlayerDisconnectedPayload{
+		PlayerID:           clientID,
+		GracePeriodSeconds: int(gracePeriod.Seconds()),
+	}
+	msg, _ := protocol.NewMessage("player_disconnected", payload)
+	g.broadcast(msg)
+	return true
+}
+
+// ReconnectPlayer resumes a held seat and sends the player a full snapshot.
+// The caller must already have rebound the client to playerID.
+func (g *Game) ReconnectPlayer(playerID string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.GameState == GameOver {
+		log.Printf("Game %s: Player %s tried to reconnect, but game already over.", g.ID, playerID)
+		return false
+	}
+	playerIndex := g.GetPlayerIndex(playerID)
+	if playerIndex == -1 {
+		log.Printf("Game %s: Reconnect from unknown player ID %s", g.ID, playerID)
+		return false
+	}
+
+	delete(g.disconnected, playerID)
+	log.Printf("Game %s: Player %s (%s) reconnected.", g.ID, playerID, g.Players[playerIndex].Name)
+
+	snapshotMsg, _ := protocol.NewMessage("game_snapshot", g.snapshot(playerIndex))
+	g.sendToPlayer(playerID, snapshotMsg)
+
+	reconnectedPayload := protocol.PlayerReconnectedPayload{PlayerID: playerID}
+	reconnectedMsg, _ := protocol.NewMessage("player_reconnected", reconnectedPayload)
+	g.broadcast(reconnectedMsg)
+
+	// Resume play once every held seat is back
+	if len(g.disconnected) == 0 {
+		switch g.GameState {
+		case Playing:
+			g.startTurnClock()
+			g.broadcastGameState()
+			g.notifyCurrentPlayerTurn()
+		case Calling:
+			g.startTurnClock()
+			g.broadcastGameState()
+			g.requestCall()
+		case Declaring:
+			if time.Now().After(g.declarationDeadline) {
+				g.finishDeclarations() // The phase timed out while the seat was held
+			} else {
+				g.broadcastDeclarationPhase()
+			}
+		case RoundOver:
+			if g.ready != nil && time.Now().Before(g.readyDeadline) {
+				g.broadcastReadyCheck()
+			} else {
+				g.ready = nil // The ready check timed out, or was skipped, while the seat was held
+				g.startRound()
+			}
+		}
+	}
+	return true
+}
+
+// snapshot builds the full table view for the player at playerIndex, or for a
+// spectator (no hand) when playerIndex is -1. Assumes lock is held.
+func (g *Game) snapshot(playerIndex int) protocol.GameSnapshotPayload {
+	playerID := ""
+	hand := []shared.Card{}
+	if playerIndex != -1 {
+		player := g.Players[playerIndex]
+		playerID = player.ID
+		hand = make([]shared.Card, len(player.Hand))
+		copy(hand, player.Hand)
+	}
+	declarations := make([]protocol.DeclarationConfirmationPayload, len(g.declarationsMade))
+	copy(declarations, g.declarationsMade)
+
+	return protocol.GameSnapshotPayload{
+		GameStartPayload: g.gameStartPayload(),
+		PlayerID:         playerID,
+		Hand:             hand,
+		CardsOnTable:     g.CardsOnTable,
+		CurrentPlayerID:  g.Players[g.PlayerTurnIndex].ID,
+		Team1Score:       g.Teams[0].Score,
+		Team2Score:       g.Teams[1].Score,
+		Team1TotalScore:  g.Teams[0].TotalScore,
+		Team2TotalScore:  g.Teams[1].TotalScore,
+		Scores:           g.roundScores(),
+		TotalScores:      g.totalScores(),
+		Declarations:     declarations,
+		StockRemaining:   len(g.Deck.Cards),
+		Trump:            g.Trump,
+		GameState:        string(g.GameState),
+		Eliminated:       g.eliminatedIDs(),
+		Call:             g.callPayload(),
+		PartnerID:        g.knownPartnerID(playerIndex),
+		SignalHistory:    append([]protocol.SignalRecord{}, g.signalHistory...),
+		Commitment:       g.seed.Commitment(),
+		Timeouts:         g.timeoutCounts(),
+		ScoreSheet:       append([]protocol.RoundScoreSheet{}, g.scoreSheet...),
+	}
+}
+
+// ForfeitPlayer ends the game in favour of the other team after a player has left for good.
+func (g *Game) ForfeitPlayer(clientID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.GameState == GameOver {
+		log.Printf("Game %s: Player %s forfeited, but game already over.", g.ID, clientID)
+		return // Game already over
+	}
+
+	playerIndex := g.GetPlayerIndex(clientID)
+	if playerIndex == -1 {
+		log.Printf("Game %s: Forfeit from unknown or already removed client ID %s", g.ID, clientID)
+		return
+	}
+
+	if g.eliminated[playerIndex] {
+		log.Printf("Game %s: Eliminated player %s left, the game goes on.", g.ID, clientID)
 		return
 	}
 
 	playerName := g.Players[playerIndex].Name
-	log.Printf("Game %s: Player %s (%s) disconnected.", g.ID, clientID, playerName)
+	log.Printf("Game %s: Player %s (%s) left the game.", g.ID, clientID, playerName)
 	g.GameState = GameOver // Forfeit the game
+	delete(g.disconnected, clientID)
 
 	// Broadcast player left message
 	leftPayload := protocol.PlayerLeftPayload{PlayerID: clientID}
@@ -562,68 +920,34 @@ func (g *Game) HandlePlayerDisconnect(clientID string) {
 	g.broadcast(leftMsg) // Notify remaining players
 
 	// Broadcast game over (due to forfeit)
-	// Determine winning team (the one that didn't disconnect)
+	// Determine winning team: the best placed of the teams that didn't disconnect
+	leavingTeam := g.teamOf(playerIndex)
 	var winningTeam *shared.Team
-	if playerIndex == 0 || playerIndex == 2 { // Player was in Team 1 (index 0)
-		winningTeam = g.Teams[1] // Team 2 (index 1) wins
-	} else { // Player was in Team 2 (index 1)
-		winningTeam = g.Teams[0] // Team 1 (index 0) wins
+	if g.Mode == ModeMisere {
+		winningTeam = g.lowestTeam(playerIndex)
+	} else {
+		for _, team := range g.Teams {
+			if team != leavingTeam && (winningTeam == nil || team.TotalScore > winningTeam.TotalScore) {
+				winningTeam = team
+			}
+		}
 	}
-
-	gameOverPayload := protocol.GameOverPayload{
-		WinningTeamID: winningTeam.ID,
-		FinalScoreT1:  g.Teams[0].TotalScore,
-		FinalScoreT2:  g.Teams[1].TotalScore,
+	g.broadcastGameOver(winningTeam) // Notify remaining players
+	g.logEvent(Event{Type: EventForfeit, Seat: playerIndex, TotalScores: g.totalScores(), WinningTeam: winningTeam.TeamNumber})
+	g.saveEventLog()
+	if g.saveResult(playerIndex) {
+		g.updateRatings(winningTeam, playerIndex)
 	}
-	gameOverMsg, _ := protocol.NewMessage("game_over", gameOverPayload)
-	g.broadcast(gameOverMsg) // Notify remaining players
+	g.reportOutcome(winningTeam, clientID) // The Hub closes the table
 
-	// TODO: Signal Hub to clean up this game instance? Or Hub handles based on state?
 	// Consider saving winningTeam.TeamNumber (1 or 2) to DB instead of UUID
-	log.Printf("Game %s: Game ended due to player %s disconnect. Team %d (ID: %s) wins by forfeit.", g.ID, clientID, winningTeam.TeamNumber, winningTeam.ID)
-}
-
-func (g *Game) handleDeclaration(playerId string, declaration protocol.DeclarePayload) {
-	d := declaration.ToDeclaration()
-
-	for _, player := range g.Players {
-		if player != nil && player.ID == playerId {
-			result := player.AddDeclaration(d)
-			if !result.Success {
-				log.Printf("Game %s: Player %s failed to add declaration: %v", g.ID, playerId, d)
-				g.sendErrorToPlayer(playerId, "Invalid declaration.")
-				return
-			} else {
-				for _, team := range g.Teams {
-					for _, p := range team.Players {
-						if p != nil && p.ID == player.ID {
-							team.AddScore(result.Points * 3) // Scale points
-							log.Printf("Game %s: Player %s declared %s. Team %d (ID: %s) score updated to %d.",
-								g.ID, playerId, d, team.TeamNumber, team.ID, team.Score)
-							// Broadcast declaration
-							declarationPayload := protocol.DeclarationConfirmationPayload{
-								TeamID:      team.ID,
-								PlayerID:    player.ID,
-								Points:      result.Points * 3, // Scale points
-								Declaration: declaration,
-								WithoutSuit: result.WithoutSuit,
-							}
-							declarationMsg, _ := protocol.NewMessage("declaration_confirmation", declarationPayload)
-							g.broadcast(declarationMsg)
-
-							break
-						}
-					}
-				}
-			}
-			break
-		}
-	}
+	log.Printf("Game %s: Game ended due to player %s leaving. Team %d (ID: %s) wins by forfeit.", g.ID, clientID, winningTeam.TeamNumber, winningTeam.ID)
 }
 
 // --- Messaging Helpers (Assume lock is held or called safely) ---
 
-// broadcast sends a message to all players in the game.
+// broadcast sends a message to all players in the game and to its spectators.
+// Only public information may be broadcast; hands go through sendToPlayer.
 func (g *Game) broadcast(message []byte) {
 	if g.sendMessage == nil {
 		log.Printf("Game %s: Error - sendMessage callback is nil during broadcast.", g.ID)
@@ -631,13 +955,20 @@ func (g *Game) broadcast(message []byte) {
 	}
 	for _, player := range g.Players {
 		if player != nil {
-			g.sendMessage(player.ID, message)
+			g.sendToPlayer(player.ID, message)
 		}
 	}
+	for spectatorID := range g.spectators {
+		g.sendMessage(spectatorID, message)
+	}
 }
 
 // sendToPlayer sends a message to a specific player by ID.
 func (g *Game) sendToPlayer(playerID string, message []byte) {
+	if agent, ok := g.agents[playerID]; ok {
+		g.deliverToAgent(playerID, agent, message)
+		return
+	}
 	if g.sendMessage == nil {
 		log.Printf("Game %s: Error - sendMessage callback is nil when sending to %s.", g.ID, playerID)
 		return
@@ -669,6 +1000,13 @@ func (g *Game) broadcastError(errorMsg string) {
 
 // broadcastGameState sends the current game state to all players.
 func (g *Game) broadcastGameState() {
+	msgBytes, _ := protocol.NewMessage("game_state_update", g.gameStatePayload())
+	g.broadcast(msgBytes)
+	g.scheduleHandReveal()
+}
+
+// gameStatePayload describes the public table state. Assumes lock is held.
+func (g *Game) gameStatePayload() protocol.GameStatePayload {
 	// Create payload (ensure sensitive info like full hands isn't sent)
 	var currentPlayerID string
 	if g.PlayerTurnIndex >= 0 && g.PlayerTurnIndex < len(g.Players) && g.Players[g.PlayerTurnIndex] != nil {
@@ -690,12 +1028,20 @@ func (g *Game) broadcastGameState() {
 		CurrentPlayerID: currentPlayerID,
 		CardsOnTable:    g.CardsOnTable,
 		// are scored points needed?
-		Team1Score: team1Score,
-		Team2Score: team2Score,
-		GameState:  string(g.GameState),
+		Team1Score:     team1Score,
+		Team2Score:     team2Score,
+		Scores:         g.roundScores(),
+		StockRemaining: len(g.Deck.Cards),
+		Trump:          g.Trump,
+		GameState:      string(g.GameState),
+		TurnSeconds:    g.turnSecondsLeft(),
+		TimeBanks:      g.timeBankSeconds(),
 	}
-	msgBytes, _ := protocol.NewMessage("game_state_update", payload)
-	g.broadcast(msgBytes)
+	if g.leadSignal != "" {
+		payload.Signal = g.leadSignal
+		payload.SignalPlayerID = g.Players[g.leadSignalSeat].ID
+	}
+	return payload
 }
 
 // notifyCurrentPlayerTurn sends the 'your_turn' message.
@@ -703,7 +1049,12 @@ func (g *Game) notifyCurrentPlayerTurn() {
 	currentPlayer := g.Players[g.PlayerTurnIndex]
 
 	payload := protocol.YourTurnPayload{
-		PlayerID: currentPlayer.ID,
+		PlayerID:    currentPlayer.ID,
+		ValidMoves:  ValidMoves(g.Rules, currentPlayer.Hand, g.trickState()),
+		SecondsLeft: g.turnSecondsLeft(),
+	}
+	if g.timeBank > 0 {
+		payload.TimeBankSeconds = int(g.timeBanks[g.PlayerTurnIndex].Seconds())
 	}
 	msgBytes, _ := protocol.NewMessage("your_turn", payload)
 	g.sendToPlayer(currentPlayer.ID, msgBytes)
@@ -722,6 +1073,142 @@ func (g *Game) notifyPlayerPlayedCard(playerID string, card shared.Card) {
 
 // --- Utility Helpers ---
 
+// TeamNumber returns the team number of a player, or 0 if they aren't seated in this game.
+func (g *Game) TeamNumber(playerID string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	playerIndex := g.GetPlayerIndex(playerID)
+	if playerIndex == -1 {
+		return 0
+	}
+	return g.teamOf(playerIndex).TeamNumber
+}
+
+// teamOf returns the team the player at playerIndex plays for.
+// Teams alternate around the table, so partners sit opposite each other;
+// in two- and three-player games every player is a team of one.
+func (g *Game) teamOf(playerIndex int) *shared.Team {
+	return g.Teams[playerIndex%len(g.Teams)]
+}
+
+// activeSeats lists the seats still in the game, in turn order.
+func (g *Game) activeSeats() []int {
+	seats := make([]int, 0, len(g.Players))
+	for i := range g.Players {
+		if !g.eliminated[i] {
+			seats = append(seats, i)
+		}
+	}
+	return seats
+}
+
+// activeCount returns the number of players still in the game.
+func (g *Game) activeCount() int {
+	return len(g.activeSeats())
+}
+
+// nextActiveSeat returns the first seat after seat whose player is still in the game.
+func (g *Game) nextActiveSeat(seat int) int {
+	for i := 1; i <= len(g.Players); i++ {
+		next := (seat + i) % len(g.Players)
+		if !g.eliminated[next] {
+			return next
+		}
+	}
+	return seat
+}
+
+// roundScores lists every team's scaled round score, ordered by team number.
+func (g *Game) roundScores() []int {
+	scores := make([]int, len(g.Teams))
+	for i, team := range g.Teams {
+		scores[i] = team.Score
+	}
+	return scores
+}
+
+// totalScores lists every team's total score, ordered by team number.
+func (g *Game) totalScores() []int {
+	scores := make([]int, len(g.Teams))
+	for i, team := range g.Teams {
+		scores[i] = team.TotalScore
+	}
+	return scores
+}
+
+// broadcastGameOver announces the winner and the final scores. Assumes lock is held.
+func (g *Game) broadcastGameOver(winningTeam *shared.Team) {
+	gameOverPayload := protocol.GameOverPayload{
+		WinningTeamID:     winningTeam.ID,
+		WinningTeamNumber: winningTeam.TeamNumber,
+		FinalScoreT1:      g.Teams[0].TotalScore,
+		FinalScoreT2:      g.Teams[1].TotalScore,
+		FinalScores:       g.totalScores(),
+	}
+	gameOverMsg, _ := protocol.NewMessage("game_over", gameOverPayload)
+	g.broadcast(gameOverMsg)
+}
+
+// resultRecord builds the database row for a finished game.
+// saveResult stores the result of the finished game; leaver is the seat whose
+// forfeit ended it, or -1. Returns false if it wasn't stored. Assumes lock is held.
+func (g *Game) saveResult(leaver int) bool {
+	if g.db == nil {
+		return false
+	}
+	result := g.resultRecord()
+	if leaver != -1 {
+		result.ForfeitedBy = g.Players[leaver].Name
+	}
+	if err := g.db.Insert(result); err != nil {
+		log.Printf("Game %s: Error saving result: %v", g.ID, err)
+		return false
+	}
+	return true
+}
+
+func (g *Game) resultRecord() database.GameResult {
+	var names, accounts [4]string
+	var teams [4]int
+	i := 0
+	for _, team := range g.Teams {
+		for _, p := range team.Players {
+			names[i] = p.Name
+			accounts[i] = p.AccountID
+			teams[i] = team.TeamNumber
+			i++
+		}
+	}
+	scores := make([]database.TeamScore, len(g.Teams))
+	for i, team := range g.Teams {
+		scores[i] = database.TeamScore{TeamNumber: team.TeamNumber, Score: team.TotalScore}
+	}
+	return database.GameResult{
+		ID:          g.ID,
+		PlayerCount: len(g.Players),
+		Ruleset:     g.Rules.Name(),
+		Scores:      scores,
+		Team1Score:  g.Teams[0].TotalScore,
+		Team2Score:  g.Teams[1].TotalScore,
+		Player1:     names[0],
+		Player2:     names[1],
+		Player3:     names[2],
+		Player4:     names[3],
+		Player1Team: teams[0],
+		Player2Team: teams[1],
+		Player3Team: teams[2],
+		Player4Team: teams[3],
+		CreatedAt:   time.Now().Format(time.RFC3339),
+
+		Player1Account: accounts[0],
+		Player2Account: accounts[1],
+		Player3Account: accounts[2],
+		Player4Account: accounts[3],
+		Ranked:         g.ranked,
+	}
+}
+
 // GetPlayerByID finds a player struct by their ID.
 func (g *Game) GetPlayerByID(playerID string) *shared.Player {
 	for _, p := range g.Players {
@@ -732,7 +1219,7 @@ func (g *Game) GetPlayerByID(playerID string) *shared.Player {
 	return nil
 }
 
-// GetPlayerIndex finds the index (0-3) of a player by their ID. Returns -1 if not found.
+// GetPlayerIndex finds the seat index of a player by their ID. Returns -1 if not found.
 func (g *Game) GetPlayerIndex(playerID string) int {
 	for i, p := range g.Players {
 		// Add nil check for player
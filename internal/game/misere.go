@@ -0,0 +1,107 @@
+package game
+
+import (
+	"log"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// Tressette a perdere (also known as Ciapanò) turns the scoring around: everyone
+// plays alone and tries to take as few points as possible. Reaching the points
+// goal knocks a player out, and the last player left in wins the game.
+
+// applyCappotto flips the round when a single player took every point: they score
+// nothing and everyone else still in the game is charged those points instead.
+// Returns the seat of that player, or -1 if there was no cappotto. Assumes lock is held.
+func (g *Game) applyCappotto() int {
+	taker := -1
+	for _, seat := range g.activeSeats() {
+		if g.teamOf(seat).Score == 0 {
+			continue
+		}
+		if taker != -1 {
+			return -1 // Points were shared, no cappotto
+		}
+		taker = seat
+	}
+	if taker == -1 {
+		return -1
+	}
+
+	points := g.teamOf(taker).Score
+	for _, seat := range g.activeSeats() {
+		if seat == taker {
+			g.teamOf(seat).ResetScore()
+		} else {
+			g.teamOf(seat).AddScore(points)
+		}
+	}
+	log.Printf("Game %s: Cappotto by player %d (%s). Scaled points %d charged to everyone else.", g.ID, taker, g.Players[taker].Name, points)
+	return taker
+}
+
+// eliminatePlayers knocks out every player whose total reached the points goal.
+// If that would leave nobody in the game, the players with the lowest total stay in.
+// Returns the seats knocked out. Assumes lock is held.
+func (g *Game) eliminatePlayers() []int {
+	var over []int
+	for _, seat := range g.activeSeats() {
+		if g.teamOf(seat).TotalScore >= g.TargetScore {
+			over = append(over, seat)
+		}
+	}
+	if len(over) == 0 {
+		return nil
+	}
+
+	spareLowest := len(over) == g.activeCount()
+	lowest := g.teamOf(over[0]).TotalScore
+	for _, seat := range over[1:] {
+		if total := g.teamOf(seat).TotalScore; total < lowest {
+			lowest = total
+		}
+	}
+
+	var out []int
+	for _, seat := range over {
+		total := g.teamOf(seat).TotalScore
+		if spareLowest && total == lowest {
+			continue
+		}
+		g.eliminated[seat] = true
+		out = append(out, seat)
+		log.Printf("Game %s: Player %d (%s) eliminated with %d points.", g.ID, seat, g.Players[seat].Name, total)
+
+		payload := protocol.PlayerEliminatedPayload{PlayerID: g.Players[seat].ID, TotalScore: total}
+		msg, _ := protocol.NewMessage("player_eliminated", payload)
+		g.broadcast(msg)
+	}
+	return out
+}
+
+// lowestTeam returns the team with the lowest total among the players still in,
+// skipping the given seat. Used to pick the winner of an "a perdere" game. Assumes lock is held.
+func (g *Game) lowestTeam(skipSeat int) *shared.Team {
+	var best *shared.Team
+	for _, seat := range g.activeSeats() {
+		if seat == skipSeat {
+			continue
+		}
+		if team := g.teamOf(seat); best == nil || team.TotalScore < best.TotalScore {
+			best = team
+		}
+	}
+	return best
+}
+
+// eliminatedIDs lists the players knocked out so far. Assumes lock is held.
+func (g *Game) eliminatedIDs() []string {
+	ids := []string{}
+	for i, out := range g.eliminated {
+		if out {
+			ids = append(ids, g.Players[i].ID)
+		}
+	}
+	return ids
+}
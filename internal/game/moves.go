@@ -0,0 +1,35 @@
+package game
+
+import (
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// ValidMoves lists the cards in hand that the ruleset allows on the trick described by state.
+// It has no side effects, so clients, bots and tests can rely on it instead of repeating the rules.
+func ValidMoves(rules Ruleset, hand []shared.Card, state TrickState) []shared.Card {
+	moves := []shared.Card{}
+	for _, card := range hand {
+		if rules.IsValidPlay(hand, card, state) {
+			moves = append(moves, card)
+		}
+	}
+	return moves
+}
+
+// AvailableDeclarations lists the declarations a player could still make with
+// hand under the ruleset, given those already made this round. It has no side effects.
+func AvailableDeclarations(rules Ruleset, hand []shared.Card, made []shared.Declaration) []protocol.DeclarePayload {
+	declarations := []protocol.DeclarePayload{}
+	if !rules.AllowsDeclarations() {
+		return declarations
+	}
+	for _, d := range shared.AvailableDeclarations(hand, made) {
+		declarations = append(declarations, protocol.DeclarePayload{
+			DeclarationType: protocol.DeclareType(d.Type),
+			Suit:            d.Suit,
+			Rank:            d.Rank,
+		})
+	}
+	return declarations
+}
@@ -0,0 +1,197 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+func card(suit shared.Suit, rank string) shared.Card {
+	return shared.Card{Suit: suit, Rank: rank}
+}
+
+func mustRuleset(t *testing.T, name string) Ruleset {
+	t.Helper()
+	rules, ok := LookupRuleset(name)
+	if !ok {
+		t.Fatalf("ruleset %q not found", name)
+	}
+	return rules
+}
+
+func TestValidMoves(t *testing.T) {
+	hand := []shared.Card{
+		card(shared.Denari, "3"),
+		card(shared.Denari, "7"),
+		card(shared.Spade, "1"),
+		card(shared.Bastoni, "12"),
+	}
+
+	tests := []struct {
+		name  string
+		rules string
+		state TrickState
+		want  []shared.Card
+	}{
+		{
+			name:  "leading allows any card",
+			rules: "classic",
+			state: TrickState{},
+			want:  hand,
+		},
+		{
+			name:  "must follow the led suit",
+			rules: "classic",
+			state: TrickState{LedSuit: shared.Denari},
+			want:  []shared.Card{card(shared.Denari, "3"), card(shared.Denari, "7")},
+		},
+		{
+			name:  "any card without the led suit",
+			rules: "classic",
+			state: TrickState{LedSuit: shared.Kope},
+			want:  hand,
+		},
+		{
+			name:  "any card while the stock lasts",
+			rules: "classic",
+			state: TrickState{LedSuit: shared.Denari, StockRemaining: 10},
+			want:  hand,
+		},
+		{
+			name:  "briscola never forces following suit",
+			rules: "briscola",
+			state: TrickState{LedSuit: shared.Spade, Trump: shared.Bastoni},
+			want:  hand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidMoves(mustRuleset(t, tt.rules), hand, tt.state)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidMoves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailableDeclarations(t *testing.T) {
+	napolaAndThrees := []shared.Card{
+		card(shared.Denari, "1"),
+		card(shared.Denari, "2"),
+		card(shared.Denari, "3"),
+		card(shared.Spade, "3"),
+		card(shared.Kope, "3"),
+		card(shared.Bastoni, "7"),
+	}
+
+	tests := []struct {
+		name  string
+		rules string
+		hand  []shared.Card
+		made  []shared.Declaration
+		want  []protocol.DeclarePayload
+	}{
+		{
+			name:  "napola and three of a kind",
+			rules: "classic",
+			hand:  napolaAndThrees,
+			want: []protocol.DeclarePayload{
+				{DeclarationType: protocol.DeclareNapola, Suit: shared.Denari},
+				{DeclarationType: protocol.DeclareThreeOrFourOfKind, Rank: "3"},
+			},
+		},
+		{
+			name:  "declarations already made are left out",
+			rules: "classic",
+			hand:  napolaAndThrees,
+			made:  []shared.Declaration{{Type: "napola", Suit: shared.Denari}},
+			want: []protocol.DeclarePayload{
+				{DeclarationType: protocol.DeclareThreeOrFourOfKind, Rank: "3"},
+			},
+		},
+		{
+			name:  "nothing to declare",
+			rules: "classic",
+			hand:  []shared.Card{card(shared.Denari, "1"), card(shared.Spade, "2"), card(shared.Kope, "7")},
+			want:  []protocol.DeclarePayload{},
+		},
+		{
+			name:  "ruleset without declarations",
+			rules: "no_declarations",
+			hand:  napolaAndThrees,
+			want:  []protocol.DeclarePayload{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AvailableDeclarations(mustRuleset(t, tt.rules), tt.hand, tt.made)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AvailableDeclarations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeclare(t *testing.T) {
+	hand := []shared.Card{
+		card(shared.Denari, "1"),
+		card(shared.Denari, "2"),
+		card(shared.Denari, "3"),
+		card(shared.Spade, "3"),
+		card(shared.Kope, "3"),
+	}
+
+	tests := []struct {
+		name        string
+		rules       string
+		declaration shared.Declaration
+		wantPoints  int
+		wantSuccess bool
+	}{
+		{
+			name:        "napola",
+			rules:       "classic",
+			declaration: shared.Declaration{Type: "napola", Suit: shared.Denari},
+			wantPoints:  3,
+			wantSuccess: true,
+		},
+		{
+			name:        "napola the hand doesn't hold",
+			rules:       "classic",
+			declaration: shared.Declaration{Type: "napola", Suit: shared.Spade},
+		},
+		{
+			name:        "three of a kind of a rank that doesn't score",
+			rules:       "classic",
+			declaration: shared.Declaration{Type: "three_or_four_of_kind", Rank: "7"},
+		},
+		{
+			name:        "unknown type",
+			rules:       "classic",
+			declaration: shared.Declaration{Type: "royal_flush", Suit: shared.Denari},
+		},
+		{
+			name:        "ruleset without declarations",
+			rules:       "no_declarations",
+			declaration: shared.Declaration{Type: "napola", Suit: shared.Denari},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := shared.NewPlayer("p0", "Player 0", 0)
+			player.Hand = hand
+			got := mustRuleset(t, tt.rules).Declare(player, tt.declaration)
+			if got.Success != tt.wantSuccess || got.Points != tt.wantPoints {
+				t.Errorf("Declare() = %+v, want success %v with %d points", got, tt.wantSuccess, tt.wantPoints)
+			}
+			if recorded := len(player.Declarations) == 1; recorded != tt.wantSuccess {
+				t.Errorf("declaration recorded = %v, want %v", recorded, tt.wantSuccess)
+			}
+		})
+	}
+}
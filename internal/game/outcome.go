@@ -0,0 +1,50 @@
+package game
+
+import (
+	"tressette-game/internal/shared"
+)
+
+// Outcome is how a finished game ended, as reported to the function given to OnGameOver.
+type Outcome struct {
+	GameID      string
+	WinningTeam int    // Team number of the winner
+	Scores      []int  // Final total of every team, ordered by team number
+	LeaverID    string // Player whose forfeit ended the game, empty if it was played out
+}
+
+// OnGameOver registers a function to call once the game is over. It is called
+// from its own goroutine, so it may call back into the game.
+// Must be called before StartGameLoop.
+func (g *Game) OnGameOver(fn func(Outcome)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onGameOver = fn
+}
+
+// reportOutcome hands the result of the game to the OnGameOver function. Assumes lock is held.
+func (g *Game) reportOutcome(winningTeam *shared.Team, leaverID string) {
+	if g.onGameOver == nil {
+		return
+	}
+	outcome := Outcome{
+		GameID:      g.ID,
+		WinningTeam: winningTeam.TeamNumber,
+		Scores:      g.totalScores(),
+		LeaverID:    leaverID,
+	}
+	go g.onGameOver(outcome)
+}
+
+// Seating returns the players in seat order, each asking for the team they
+// played for, so that a new game started with them keeps the same seats.
+func (g *Game) Seating() []*shared.Player {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	players := make([]*shared.Player, len(g.Players))
+	for i, p := range g.Players {
+		players[i] = shared.NewPlayer(p.ID, p.Name, shared.TeamEnum(g.teamOf(i).TeamNumber))
+		players[i].AccountID = p.AccountID
+	}
+	return players
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"tressette-game/internal/protocol"
+)
+
+// The server paces the table so clients don't have to fake delays: after a
+// trick the cards stay on the table for the trick pause before the next lead is
+// asked for, and after a round every player confirms they are ready (or the
+// ready timeout runs out) before the next deal.
+
+// pauseAfterTrick leaves the finished trick on show before play goes on. Assumes lock is held.
+func (g *Game) pauseAfterTrick() {
+	if g.trickPause <= 0 {
+		g.resumeAfterTrick()
+		return
+	}
+
+	g.GameState = TrickOver
+	g.pause++
+	pause := g.pause
+	time.AfterFunc(g.trickPause, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.GameState != TrickOver || g.pause != pause {
+			return
+		}
+		g.resumeAfterTrick()
+	})
+}
+
+// resumeAfterTrick asks the trick winner to lead. If a seat is held for a
+// reconnect, ReconnectPlayer resumes play instead. Assumes lock is held.
+func (g *Game) resumeAfterTrick() {
+	g.GameState = Playing
+	log.Printf("Game %s: Next trick. Player %d (%s)'s turn.", g.ID, g.PlayerTurnIndex, g.Players[g.PlayerTurnIndex].Name)
+	if len(g.disconnected) > 0 {
+		return
+	}
+
+	// Broadcast state update (shows empty table) and notify next player
+	g.startTurnClock()
+	g.broadcastGameState()
+	g.notifyCurrentPlayerTurn()
+}
+
+// startReadyCheck waits for every player still in to confirm they are ready for
+// the next round, which starts once they all have or the ready timeout runs out.
+// If a seat is held for a reconnect when it would start, ReconnectPlayer starts
+// it instead. Assumes lock is held.
+func (g *Game) startReadyCheck() {
+	if g.readyTimeout <= 0 {
+		if len(g.disconnected) > 0 {
+			log.Printf("Game %s: Waiting for a reconnect before the next round.", g.ID)
+			return
+		}
+		g.startRound()
+		return
+	}
+
+	g.ready = make([]bool, len(g.Players))
+	for i := range g.Players {
+		g.ready[i] = g.eliminated[i]
+	}
+	g.pause++
+	pause := g.pause
+	g.readyDeadline = time.Now().Add(g.readyTimeout)
+	log.Printf("Game %s: Waiting for players to be ready (%s).", g.ID, g.readyTimeout)
+
+	time.AfterFunc(g.readyTimeout, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.GameState != RoundOver || g.pause != pause {
+			return
+		}
+		if len(g.disconnected) > 0 {
+			log.Printf("Game %s: Ready check timed out, waiting for a reconnect.", g.ID)
+			return
+		}
+		log.Printf("Game %s: Ready check timed out.", g.ID)
+		g.ready = nil
+		g.startRound()
+	})
+
+	g.broadcastReadyCheck()
+}
+
+// handleReady marks a player as ready and starts the next round once nobody is
+// left. Assumes lock is held.
+func (g *Game) handleReady(playerIndex int) {
+	if g.ready[playerIndex] {
+		return
+	}
+	g.ready[playerIndex] = true
+	log.Printf("Game %s: Player %d (%s) is ready.", g.ID, playerIndex, g.Players[playerIndex].Name)
+
+	if len(g.notReadyIDs()) == 0 {
+		g.ready = nil
+		g.startRound()
+		return
+	}
+	g.broadcastReadyCheck()
+}
+
+// notReadyIDs lists the players who have not confirmed they are ready. Assumes lock is held.
+func (g *Game) notReadyIDs() []string {
+	ids := []string{}
+	for i, ready := range g.ready {
+		if !ready {
+			ids = append(ids, g.Players[i].ID)
+		}
+	}
+	return ids
+}
+
+// broadcastReadyCheck tells everyone who the next round is waiting for. Assumes lock is held.
+func (g *Game) broadcastReadyCheck() {
+	payload := protocol.ReadyCheckPayload{
+		PendingPlayerIDs: g.notReadyIDs(),
+		SecondsLeft:      int(time.Until(g.readyDeadline).Seconds()),
+	}
+	msg, _ := protocol.NewMessage("ready_check", payload)
+	g.broadcast(msg)
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+func TestReadyCheckWaitsForReconnect(t *testing.T) {
+	const readyTimeout = 10 * time.Millisecond
+	players := make([]*shared.Player, TwoPlayers)
+	for i := range players {
+		players[i] = shared.NewPlayer(fmt.Sprintf("p%d", i), fmt.Sprintf("Player %d", i), 0)
+	}
+	g := NewGame(players, Settings{
+		TargetScore:  100,
+		Rules:        mustRuleset(t, "no_declarations"),
+		ReadyTimeout: readyTimeout,
+	}, nil)
+	g.StartGameLoop(func(string, []byte) {})
+
+	for g.GameState == Playing {
+		p := g.Players[g.PlayerTurnIndex]
+		card := ValidMoves(g.Rules, p.Hand, g.trickState())[0]
+		g.HandlePlayerAction(p.ID, action(t, "play_card", protocol.PlayCardPayload{Suit: card.Suit, Rank: card.Rank}))
+	}
+	if g.GameState != RoundOver {
+		t.Fatalf("round ended in state %s", g.GameState)
+	}
+	g.HandlePlayerDisconnect("p1", time.Minute)
+
+	time.Sleep(readyTimeout + 20*time.Millisecond)
+	g.mu.Lock()
+	state, round := g.GameState, g.round
+	g.mu.Unlock()
+	if state != RoundOver {
+		t.Fatalf("state = %s after the ready timeout while a seat is held, want %s", state, RoundOver)
+	}
+
+	g.ReconnectPlayer("p1")
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.round != round+1 {
+		t.Errorf("round = %d after the reconnect, want %d", g.round, round+1)
+	}
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"tressette-game/internal/database"
+	"tressette-game/internal/shared"
+)
+
+// Ranked games rate registered players with Elo. A side is rated as the
+// average of its players, and every rated player of a side moves by the same
+// amount. A player who forfeits takes the loss alone: their partner's rating
+// is left as it was.
+
+const (
+	InitialRating = 1500 // Rating of a player before their first ranked game
+	RatingK       = 32   // Most a rating can move in one game
+)
+
+// ExpectedScore is the chance Elo gives a side rated rating to beat a side rated opponent.
+func ExpectedScore(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// updateRatings stores the new ratings after a ranked game. leaver is the seat
+// whose forfeit ended the game, or -1 if it was played out. Only games whose
+// result was stored are rated, so every rating change links to a result.
+// Assumes lock is held.
+func (g *Game) updateRatings(winningTeam *shared.Team, leaver int) {
+	if !g.ranked || len(g.Teams) != 2 {
+		return
+	}
+
+	var ids []string
+	for _, p := range g.Players {
+		if p.AccountID != "" {
+			ids = append(ids, p.AccountID)
+		}
+	}
+	current, err := g.db.GetRatings(ids)
+	if err != nil {
+		log.Printf("Game %s: Error loading ratings: %v", g.ID, err)
+		return
+	}
+	ratingOf := func(p *shared.Player) int {
+		if r, ok := current[p.AccountID]; ok {
+			return r.Rating
+		}
+		return InitialRating
+	}
+
+	averages := make([]float64, len(g.Teams))
+	for i, team := range g.Teams {
+		for _, p := range team.Players {
+			averages[i] += float64(ratingOf(p))
+		}
+		averages[i] /= float64(len(team.Players))
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	var changes []database.RatingChange
+	for seat, p := range g.Players {
+		team := g.teamOf(seat)
+		if p.AccountID == "" || (leaver >= 0 && seat != leaver && team == g.teamOf(leaver)) {
+			continue
+		}
+		side := 0
+		if team != g.Teams[0] {
+			side = 1
+		}
+		score := 0.0
+		if team == winningTeam {
+			score = 1
+		}
+		before := ratingOf(p)
+		delta := math.Round(RatingK * (score - ExpectedScore(averages[side], averages[1-side])))
+		changes = append(changes, database.RatingChange{
+			AccountID:    p.AccountID,
+			GameID:       g.ID,
+			RatingBefore: before,
+			RatingAfter:  before + int(delta),
+			CreatedAt:    now,
+		})
+	}
+
+	if err := g.db.ApplyRatingChanges(changes); err != nil {
+		log.Printf("Game %s: Error saving ratings: %v", g.ID, err)
+	}
+}
@@ -0,0 +1,253 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// ReplayState is the table as it stood right after one event of a game's log.
+type ReplayState struct {
+	Event    Event                        `json:"event"`
+	Snapshot protocol.GameSnapshotPayload `json:"snapshot"` // Public view of the table
+	Hands    map[string][]shared.Card     `json:"hands"`    // Every player's hand, by player ID
+}
+
+// Rebuild replays a game's log up to and including the event with sequence number
+// step and returns the resulting game. Deals are redone from their seed and checked
+// against the recorded hands; the rebuilt game sends no messages and saves nothing.
+func Rebuild(events []Event, step int) (*Game, error) {
+	if len(events) == 0 || events[0].Type != EventGameStart || events[0].Start == nil {
+		return nil, fmt.Errorf("log does not start with %s", EventGameStart)
+	}
+	if step < 0 || step >= len(events) {
+		return nil, fmt.Errorf("step %d out of range, the log has %d events", step, len(events))
+	}
+
+	g, err := newReplayGame(events[0].Start)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events[1 : step+1] {
+		if err := g.applyEvent(event); err != nil {
+			return nil, fmt.Errorf("event %d (%s): %w", event.Seq, event.Type, err)
+		}
+	}
+	return g, nil
+}
+
+// RebuildState rebuilds the game at the given step and describes the table with every hand shown.
+func RebuildState(events []Event, step int) (ReplayState, error) {
+	g, err := Rebuild(events, step)
+	if err != nil {
+		return ReplayState{}, err
+	}
+	hands := make(map[string][]shared.Card, len(g.Players))
+	for _, p := range g.Players {
+		hands[p.ID] = append([]shared.Card{}, p.Hand...)
+	}
+	return ReplayState{
+		Event:    events[step],
+		Snapshot: g.snapshot(-1),
+		Hands:    hands,
+	}, nil
+}
+
+// newReplayGame seats the players and teams described by a game_start event.
+func newReplayGame(start *protocol.GameStartPayload) (*Game, error) {
+	rules, ok := LookupRuleset(start.Ruleset)
+	if !ok {
+		return nil, fmt.Errorf("unknown ruleset %q", start.Ruleset)
+	}
+	players := make([]*shared.Player, len(start.Players))
+	for _, info := range start.Players {
+		if info.Position < 0 || info.Position >= len(players) {
+			return nil, fmt.Errorf("player %s has invalid seat %d", info.ID, info.Position)
+		}
+		players[info.Position] = shared.NewPlayer(info.ID, info.Name, 0)
+	}
+	for seat, p := range players {
+		if p == nil {
+			return nil, fmt.Errorf("seat %d is empty", seat)
+		}
+	}
+	var teams []*shared.Team
+	for _, info := range start.Teams {
+		team := shared.NewTeam(info.TeamNumber)
+		team.ID = info.ID
+		for _, member := range info.Players {
+			team.Players = append(team.Players, players[member.Position])
+		}
+		teams = append(teams, team)
+	}
+
+	return &Game{
+		ID:                   start.GameID,
+		Players:              players,
+		Teams:                teams,
+		Deck:                 &shared.Deck{},
+		CurrentTrick:         shared.NewTrick(),
+		GameState:            Waiting,
+		TargetScore:          start.PointsGoal,
+		Mode:                 rules.Mode(),
+		Rules:                rules,
+		CardsOnTable:         []shared.Card{},
+		LastTrickWinnerIndex: -1,
+		sendMessage:          func(string, []byte) {},
+		disconnected:         make(map[string]bool),
+		agents:               make(map[string]Agent),
+		eliminated:           make([]bool, len(players)),
+		partnerIndex:         -1,
+		spectators:           make(map[string]bool),
+		signalsAllowed:       start.Signals,
+		timeBanks:            make([]time.Duration, len(players)),
+		timeouts:             make([]int, len(players)),
+	}, nil
+}
+
+// applyEvent brings the rebuilt game to the state right after the event.
+// Scores are taken from the log rather than worked out again.
+func (g *Game) applyEvent(event Event) error {
+	if event.Seat >= len(g.Players) {
+		return fmt.Errorf("invalid seat %d", event.Seat)
+	}
+
+	switch event.Type {
+	case EventDeal:
+		g.round = event.Round
+		g.tricksPlayed = 0
+		g.leadSignal = ""
+		g.declarationsMade = nil
+		g.CardsOnTable = []shared.Card{}
+		g.CurrentTrick = shared.NewTrick()
+		g.LedSuit = ""
+		g.PlayerTurnIndex = event.Seat
+		for _, team := range g.Teams {
+			team.ResetScore()
+		}
+		if event.Seed == nil {
+			return fmt.Errorf("missing seed")
+		}
+		g.seed = *event.Seed
+		hands := g.deal(g.seed)
+		if len(hands) != len(event.Hands) {
+			return fmt.Errorf("seed %s does not deal the recorded hands", g.seed)
+		}
+		for i, hand := range hands {
+			if !slices.Equal(hand, event.Hands[i]) {
+				return fmt.Errorf("seed %s does not deal the recorded hands", g.seed)
+			}
+			g.Players[i].Hand = hand
+			g.Players[i].Declarations = []shared.Declaration{}
+		}
+		switch {
+		case g.Mode == ModeChiamare:
+			g.GameState = Calling
+			g.callerIndex = event.Seat
+			g.calledCard = nil
+			g.playsAlone = false
+			g.partnerIndex = -1
+			g.partnerRevealed = false
+		case g.Rules.AllowsDeclarations():
+			g.GameState = Declaring
+		default:
+			g.GameState = Playing
+		}
+
+	case EventCall:
+		g.playsAlone = event.Alone
+		g.calledCard = event.Card
+		if event.Card != nil {
+			for i, p := range g.Players {
+				if _, holds := p.FindCard(event.Card.Suit, event.Card.Rank); holds {
+					g.partnerIndex = i
+				}
+			}
+		}
+		g.GameState = Declaring
+		if !g.Rules.AllowsDeclarations() {
+			g.GameState = Playing
+		}
+
+	case EventDeclaration:
+		if event.Declaration == nil {
+			return fmt.Errorf("missing declaration")
+		}
+		player := g.Players[event.Seat]
+		player.Declarations = append(player.Declarations, event.Declaration.Declaration.ToDeclaration())
+		g.teamOf(event.Seat).AddScore(event.Declaration.Points)
+		g.declarationsMade = append(g.declarationsMade, *event.Declaration)
+
+	case EventPlay:
+		if event.Card == nil || event.Seat < 0 {
+			return fmt.Errorf("missing card or seat")
+		}
+		if !g.Players[event.Seat].RemoveCard(*event.Card) {
+			return fmt.Errorf("%s %s is not in the hand of seat %d", event.Card.Rank, event.Card.Suit, event.Seat)
+		}
+		g.GameState = Playing
+		if len(g.CurrentTrick.Cards) == 0 {
+			g.LedSuit = event.Card.Suit
+			g.recordSignal(event.Seat, *event.Card, event.Signal)
+		}
+		g.CurrentTrick.AddCard(*event.Card, event.Seat)
+		g.CardsOnTable = append(g.CardsOnTable, *event.Card)
+		g.revealPartnerIfCalled(*event.Card)
+		g.PlayerTurnIndex = g.nextActiveSeat(event.Seat)
+
+	case EventTrickEnd:
+		if event.Seat < 0 {
+			return fmt.Errorf("missing trick winner")
+		}
+		g.teamOf(event.Seat).AddScore(event.Points)
+		g.LastTrickWinnerIndex = event.Seat
+		g.CardsOnTable = []shared.Card{}
+		g.CurrentTrick = shared.NewTrick()
+		g.LedSuit = ""
+		g.leadSignal = ""
+		g.tricksPlayed++
+		g.PlayerTurnIndex = event.Seat
+
+	case EventDraw:
+		card, ok := g.Deck.Draw()
+		if !ok || event.Card == nil || card != *event.Card || event.Seat < 0 {
+			return fmt.Errorf("the stock does not match the recorded draw")
+		}
+		g.Players[event.Seat].AddCard(card)
+
+	case EventRoundEnd:
+		if len(event.TotalScores) != len(g.Teams) {
+			return fmt.Errorf("expected %d total scores, got %d", len(g.Teams), len(event.TotalScores))
+		}
+		for i, team := range g.Teams {
+			team.ResetScore()
+			team.TotalScore = event.TotalScores[i]
+		}
+		for _, seat := range event.Eliminated {
+			if seat < 0 || seat >= len(g.Players) {
+				return fmt.Errorf("invalid eliminated seat %d", seat)
+			}
+			g.eliminated[seat] = true
+		}
+		if event.ScoreSheet != nil {
+			g.scoreSheet = append(g.scoreSheet, *event.ScoreSheet)
+		}
+		g.GameState = RoundOver
+
+	case EventTimeout:
+		if event.Seat < 0 {
+			return fmt.Errorf("missing seat")
+		}
+		g.timeouts[event.Seat]++
+
+	case EventGameOver, EventForfeit:
+		g.GameState = GameOver
+
+	default:
+		return fmt.Errorf("unknown event type")
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// tableState is the part of a game that a replay must reproduce.
+type tableState struct {
+	State        GameState
+	Turn         int
+	TricksPlayed int
+	Hands        [][]shared.Card
+	OnTable      []shared.Card
+	Scores       []int
+	Totals       []int
+}
+
+func stateOf(g *Game) tableState {
+	s := tableState{
+		State:        g.GameState,
+		Turn:         g.PlayerTurnIndex,
+		TricksPlayed: g.tricksPlayed,
+		OnTable:      append([]shared.Card{}, g.CardsOnTable...),
+	}
+	for _, p := range g.Players {
+		s.Hands = append(s.Hands, append([]shared.Card{}, p.Hand...))
+	}
+	for _, t := range g.Teams {
+		s.Scores = append(s.Scores, t.Score)
+		s.Totals = append(s.Totals, t.TotalScore)
+	}
+	return s
+}
+
+func action(t *testing.T, msgType string, payload interface{}) protocol.Message {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return protocol.Message{Type: msgType, Payload: data}
+}
+
+// playRound plays one round of g by hand, calling a card, declaring everything
+// and playing the first legal card. It checkpoints the table after the deal and
+// after every card played, keyed by the last event logged.
+func playRound(t *testing.T, g *Game) map[int]tableState {
+	t.Helper()
+	checkpoints := map[int]tableState{}
+	checkpoint := func() { checkpoints[len(g.events)-1] = stateOf(g) }
+	checkpoint()
+
+	for g.GameState != RoundOver && g.GameState != GameOver {
+		switch g.GameState {
+		case Calling:
+			caller := g.Players[g.callerIndex]
+			for _, card := range shared.NewDeck().Cards {
+				if _, held := caller.FindCard(card.Suit, card.Rank); !held {
+					g.HandlePlayerAction(caller.ID, action(t, "call_card", protocol.CallCardPayload{Suit: card.Suit, Rank: card.Rank}))
+					break
+				}
+			}
+		case Declaring:
+			for _, p := range g.Players {
+				for _, d := range AvailableDeclarations(g.Rules, p.Hand, p.Declarations) {
+					g.HandlePlayerAction(p.ID, action(t, "declare", d))
+				}
+				g.HandlePlayerAction(p.ID, protocol.Message{Type: "declarations_done"})
+			}
+		case Playing:
+			p := g.Players[g.PlayerTurnIndex]
+			card := ValidMoves(g.Rules, p.Hand, g.trickState())[0]
+			g.HandlePlayerAction(p.ID, action(t, "play_card", protocol.PlayCardPayload{Suit: card.Suit, Rank: card.Rank}))
+			checkpoint()
+		default:
+			t.Fatalf("unexpected state %s", g.GameState)
+		}
+	}
+	return checkpoints
+}
+
+func TestRebuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleset string
+		players int
+	}{
+		{name: "classic with declarations", ruleset: "classic", players: FourPlayers},
+		{name: "heads-up with a stock", ruleset: "no_declarations", players: TwoPlayers},
+		{name: "terziglio", ruleset: "classic", players: ThreePlayers},
+		{name: "a chiamare", ruleset: string(ModeChiamare), players: FourPlayers},
+		{name: "briscola", ruleset: "briscola", players: TwoPlayers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := make([]*shared.Player, tt.players)
+			for i := range players {
+				players[i] = shared.NewPlayer(fmt.Sprintf("p%d", i), fmt.Sprintf("Player %d", i), 0)
+			}
+			g := NewGame(players, Settings{
+				TargetScore:  100,
+				MortoRule:    MortoAside,
+				Rules:        mustRuleset(t, tt.ruleset),
+				ReadyTimeout: time.Hour,
+			}, nil)
+			g.StartGameLoop(func(string, []byte) {})
+
+			checkpoints := playRound(t, g)
+			if g.GameState != RoundOver {
+				t.Fatalf("round ended in state %s", g.GameState)
+			}
+			for step, want := range checkpoints {
+				rebuilt, err := Rebuild(g.events, step)
+				if err != nil {
+					t.Fatalf("Rebuild(step %d): %v", step, err)
+				}
+				if got := stateOf(rebuilt); !reflect.DeepEqual(got, want) {
+					t.Errorf("Rebuild(step %d) = %+v, want %+v", step, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRebuildRejectsBadLogs(t *testing.T) {
+	start := Event{Type: EventGameStart, Seat: -1, Start: &protocol.GameStartPayload{Ruleset: "classic"}}
+
+	tests := []struct {
+		name   string
+		events []Event
+		step   int
+	}{
+		{name: "empty log", events: nil, step: 0},
+		{name: "no game start", events: []Event{{Type: EventDeal}}, step: 0},
+		{name: "step out of range", events: []Event{start}, step: 1},
+		{name: "deal without a seed", events: []Event{start, {Type: EventDeal}}, step: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Rebuild(tt.events, tt.step); err == nil {
+				t.Errorf("Rebuild() succeeded, want an error")
+			}
+		})
+	}
+}
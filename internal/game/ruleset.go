@@ -0,0 +1,238 @@
+package game
+
+import (
+	"tressette-game/internal/shared"
+)
+
+// Ruleset holds every rule that differs between variants of the game. The Game
+// runs the state machine (dealing, turns, tricks, rounds) and asks its ruleset
+// whenever a decision depends on the variant being played.
+type Ruleset interface {
+	Name() string        // Identifier used in create_game
+	Description() string // Short human readable summary
+	Mode() Mode          // Structure of the game: fixed teams, a perdere or a chiamare
+
+	SupportsPlayerCount(players int) bool
+	TargetScore(requested int) int // Points goal, given the one chosen in the lobby
+	HandSize(players int) int      // Cards dealt to each player still in the game
+	HasTrump() bool                // Whether a trump card is turned up after the deal
+	RevealsDraws() bool            // Whether cards drawn from the stock are shown to everyone
+
+	IsValidPlay(hand []shared.Card, card shared.Card, trick TrickState) bool
+	TrickWinner(trick *shared.Trick, state TrickState) shared.PlayedCard
+	TrickPoints(cards []shared.Card) int // Scaled by 3
+	LastTrickBonus() int                 // Scaled by 3
+	SettleRound(scores []int) []int      // Turns the teams' scaled trick points into round scores
+
+	AllowsDeclarations() bool
+	Declare(player *shared.Player, declaration shared.Declaration) shared.DeclarationResult
+
+	// Winner returns the index of the winning total, or -1 if the game goes on.
+	Winner(totals []int, target int) int
+}
+
+// TrickState is what a ruleset needs to know about the trick in progress.
+type TrickState struct {
+	LedSuit        shared.Suit // Empty while nobody has played to the trick
+	Trump          shared.Suit // Empty unless the ruleset plays with trumps
+	StockRemaining int         // Cards left to draw
+}
+
+// StandardRules is Tressette as described in the rules page, with the knobs our
+// house rules need. All built-in variants are configurations of it.
+type StandardRules struct {
+	RulesName       string
+	Summary         string
+	GameMode        Mode
+	PlayerCounts    []int // Table sizes the variant can be played with
+	Declarations    bool  // Whether napolas and three/four of a kinds score
+	FixedTarget     int   // Points goal imposed by the variant, 0 keeps the lobby's choice
+	LastTrickPoints int   // Scaled bonus for taking the last trick
+}
+
+func (r *StandardRules) Name() string        { return r.RulesName }
+func (r *StandardRules) Description() string { return r.Summary }
+func (r *StandardRules) Mode() Mode          { return r.GameMode }
+
+func (r *StandardRules) SupportsPlayerCount(players int) bool {
+	for _, n := range r.PlayerCounts {
+		if n == players {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *StandardRules) TargetScore(requested int) int {
+	if r.FixedTarget > 0 {
+		return r.FixedTarget
+	}
+	return requested
+}
+
+// HandSize deals ten cards each, or thirteen when three players share the deck.
+func (r *StandardRules) HandSize(players int) int {
+	if players == ThreePlayers {
+		return TerziglioCardsPerPlayer
+	}
+	return CardsPerPlayer
+}
+
+// IsValidPlay enforces following suit, which only applies once the stock is empty.
+func (r *StandardRules) IsValidPlay(hand []shared.Card, card shared.Card, trick TrickState) bool {
+	if trick.LedSuit == "" {
+		return true // Can lead with any card
+	}
+	if trick.StockRemaining > 0 {
+		return true // No obligation to follow suit while the stock lasts
+	}
+	for _, c := range hand {
+		if c.Suit == trick.LedSuit {
+			return card.Suit == trick.LedSuit // Must follow suit if possible
+		}
+	}
+	return true // Can play any card if unable to follow suit
+}
+
+func (r *StandardRules) HasTrump() bool     { return false }
+func (r *StandardRules) RevealsDraws() bool { return true }
+
+func (r *StandardRules) TrickWinner(trick *shared.Trick, state TrickState) shared.PlayedCard {
+	return trick.DetermineWinner(state.LedSuit)
+}
+
+func (r *StandardRules) TrickPoints(cards []shared.Card) int {
+	scaledPoints := 0
+	for _, card := range cards {
+		scaledPoints += card.Value // Values are already scaled
+	}
+	return scaledPoints
+}
+
+func (r *StandardRules) LastTrickBonus() int { return r.LastTrickPoints }
+
+func (r *StandardRules) SettleRound(scores []int) []int { return scores }
+
+func (r *StandardRules) AllowsDeclarations() bool { return r.Declarations }
+
+func (r *StandardRules) Declare(player *shared.Player, declaration shared.Declaration) shared.DeclarationResult {
+	if !r.Declarations {
+		return shared.DeclarationResult{Success: false}
+	}
+	return player.AddDeclaration(declaration)
+}
+
+// Winner picks the single highest total at or above the target. When playing a
+// perdere the totals are those of the players still in, and the last one left wins.
+func (r *StandardRules) Winner(totals []int, target int) int {
+	if r.GameMode == ModeMisere {
+		if len(totals) == 1 {
+			return 0
+		}
+		return -1
+	}
+	return highestAtTarget(totals, target)
+}
+
+// highestAtTarget returns the index of the single highest total if it reached the target, or -1.
+func highestAtTarget(totals []int, target int) int {
+	leader := -1
+	tied := false
+	for i, total := range totals {
+		switch {
+		case leader == -1 || total > totals[leader]:
+			leader = i
+			tied = false
+		case total == totals[leader]:
+			tied = true
+		}
+	}
+	if leader == -1 || tied || totals[leader] < target {
+		return -1
+	}
+	return leader
+}
+
+// DefaultRuleset is used when create_game doesn't name one.
+const DefaultRuleset = "classic"
+
+// lastTrickPoint is the usual scaled bonus for the last trick.
+const lastTrickPoint = 3
+
+var allTableSizes = []int{TwoPlayers, ThreePlayers, FourPlayers}
+
+// rulesets lists the built-in variants in the order they are offered to players.
+var rulesets = []Ruleset{
+	&StandardRules{
+		RulesName:       "classic",
+		Summary:         "Classic Tressette with declarations",
+		GameMode:        ModeClassic,
+		PlayerCounts:    allTableSizes,
+		Declarations:    true,
+		LastTrickPoints: lastTrickPoint,
+	},
+	&StandardRules{
+		RulesName:       "no_declarations",
+		Summary:         "Classic Tressette, only tricks score",
+		GameMode:        ModeClassic,
+		PlayerCounts:    allTableSizes,
+		LastTrickPoints: lastTrickPoint,
+	},
+	&StandardRules{
+		RulesName:       "to_21",
+		Summary:         "Classic Tressette played to 21 points",
+		GameMode:        ModeClassic,
+		PlayerCounts:    allTableSizes,
+		Declarations:    true,
+		FixedTarget:     21,
+		LastTrickPoints: lastTrickPoint,
+	},
+	&StandardRules{
+		RulesName:       "to_31",
+		Summary:         "Classic Tressette played to 31 points",
+		GameMode:        ModeClassic,
+		PlayerCounts:    allTableSizes,
+		Declarations:    true,
+		FixedTarget:     31,
+		LastTrickPoints: lastTrickPoint,
+	},
+	&StandardRules{
+		RulesName:       "last_trick_double",
+		Summary:         "Classic Tressette where the last trick is worth 2 points",
+		GameMode:        ModeClassic,
+		PlayerCounts:    allTableSizes,
+		Declarations:    true,
+		LastTrickPoints: 2 * lastTrickPoint,
+	},
+	&StandardRules{
+		RulesName:       string(ModeMisere),
+		Summary:         "Tressette a perdere: take as few points as possible",
+		GameMode:        ModeMisere,
+		PlayerCounts:    allTableSizes,
+		LastTrickPoints: lastTrickPoint,
+	},
+	&StandardRules{
+		RulesName:       string(ModeChiamare),
+		Summary:         "Tressette a chiamare: call a card to find your partner",
+		GameMode:        ModeChiamare,
+		PlayerCounts:    []int{FourPlayers},
+		Declarations:    true,
+		LastTrickPoints: lastTrickPoint,
+	},
+	&BriscolaRules{},
+}
+
+// Rulesets returns the built-in variants.
+func Rulesets() []Ruleset {
+	return rulesets
+}
+
+// LookupRuleset finds a built-in variant by name.
+func LookupRuleset(name string) (Ruleset, bool) {
+	for _, r := range rulesets {
+		if r.Name() == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
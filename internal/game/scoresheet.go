@@ -0,0 +1,54 @@
+package game
+
+import (
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// newRoundSheet starts an empty score sheet line for every team. Assumes lock is held.
+func (g *Game) newRoundSheet() {
+	g.roundSheet = make([]protocol.TeamRoundScore, len(g.Teams))
+	for i, team := range g.Teams {
+		g.roundSheet[i].TeamNumber = team.TeamNumber
+	}
+}
+
+// sheetLine returns the score sheet line of the team the player at playerIndex plays for.
+// Assumes lock is held.
+func (g *Game) sheetLine(playerIndex int) *protocol.TeamRoundScore {
+	return &g.roundSheet[playerIndex%len(g.Teams)]
+}
+
+// tallyTrick adds a trick taken by the player at playerIndex to the score sheet.
+// Assumes lock is held.
+func (g *Game) tallyTrick(playerIndex int, cards []shared.Card, cardPoints, lastTrickBonus, mortoPoints int) {
+	line := g.sheetLine(playerIndex)
+	for _, c := range cards {
+		switch c.Rank {
+		case "1":
+			line.Aces++
+		case "2", "3", "11", "12", "13":
+			line.Figures++
+		}
+	}
+	line.CardPoints += cardPoints
+	line.LastTrickBonus += lastTrickBonus
+	line.MortoPoints += mortoPoints
+}
+
+// closeRoundSheet completes the round's score sheet once the round has been settled
+// and the totals updated, and keeps it for the rest of the match. raw holds the
+// teams' scaled scores before the ruleset settled them, settled the ones it
+// returned and points the scaled scores added to the totals. Assumes lock is held.
+func (g *Game) closeRoundSheet(raw, settled, points []int) protocol.RoundScoreSheet {
+	for i, team := range g.Teams {
+		line := &g.roundSheet[i]
+		line.Adjustment = settled[i] - raw[i]
+		line.RoundPoints = points[i] / 3
+		line.TotalScore = team.TotalScore
+	}
+	sheet := protocol.RoundScoreSheet{Round: g.round, Teams: g.roundSheet}
+	g.scoreSheet = append(g.scoreSheet, sheet)
+	g.roundSheet = nil
+	return sheet
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"log"
+
+	"tressette-game/internal/shared"
+)
+
+// Scores are kept in thirds (scaled by 3) during a round and converted to whole
+// points when the round ends. The table settings decide what happens on the way:
+// a bonus for a cappotto, what to do with the leftover thirds, and whether
+// declarations help a team reach the points goal.
+
+// applyCappottoBonus gives the table's cappotto bonus to the team that took every
+// point of the round. Classic mode only. Assumes lock is held.
+func (g *Game) applyCappottoBonus() {
+	if g.cappottoBonus <= 0 || g.Mode != ModeClassic {
+		return
+	}
+	taker := -1
+	for i, line := range g.roundSheet {
+		if line.CardPoints+line.LastTrickBonus+line.MortoPoints == 0 {
+			continue
+		}
+		if taker != -1 {
+			return // Points were shared, no cappotto
+		}
+		taker = i
+	}
+	if taker == -1 {
+		return
+	}
+
+	bonus := g.cappottoBonus * 3 // Scaled
+	g.Teams[taker].AddScore(bonus)
+	g.roundSheet[taker].CappottoBonus = bonus
+	log.Printf("Game %s: Cappotto by team %d. Bonus of %d points awarded.", g.ID, g.Teams[taker].TeamNumber, g.cappottoBonus)
+}
+
+// carryThirdsIn adds the thirds left over from the last round to every team's
+// score. Assumes lock is held.
+func (g *Game) carryThirdsIn() {
+	for i, team := range g.Teams {
+		if g.carriedThirds[i] == 0 {
+			continue
+		}
+		team.AddScore(g.carriedThirds[i])
+		g.roundSheet[i].ThirdsCarriedIn = g.carriedThirds[i]
+		g.carriedThirds[i] = 0
+	}
+}
+
+// roundThirds rounds every team's score down to whole points and deals with the
+// leftover thirds according to the table's rounding policy, so that TransferScore
+// has nothing left to drop. Assumes lock is held.
+func (g *Game) roundThirds() {
+	leftover := 0
+	for i, team := range g.Teams {
+		thirds := team.Score % 3
+		if thirds <= 0 {
+			continue // Negative scores are left alone
+		}
+		team.AddScore(-thirds)
+		switch g.rounding {
+		case RoundCarry:
+			g.carriedThirds[i] = thirds
+			g.roundSheet[i].ThirdsCarriedOut = thirds
+		case RoundLastTrick:
+			leftover += thirds
+			g.roundSheet[i].ThirdsLost = thirds
+		default:
+			g.roundSheet[i].ThirdsLost = thirds
+		}
+	}
+	if leftover == 0 || g.LastTrickWinnerIndex == -1 {
+		return
+	}
+
+	// The last trick takes the leftover thirds of every team, its own included,
+	// rounded up to whole points. The cards and the last trick bonus always leave
+	// 2 thirds over whole points, so with two teams the leftovers are exactly 2
+	// thirds and the last trick wins one more point.
+	taker := g.LastTrickWinnerIndex % len(g.Teams)
+	won := leftover + (3-leftover%3)%3
+	for i := range g.roundSheet {
+		g.roundSheet[i].ThirdsLost = 0
+	}
+	g.Teams[taker].AddScore(won)
+	g.roundSheet[taker].ThirdsReceived = won
+	log.Printf("Game %s: Team %d takes %d leftover thirds with the last trick.", g.ID, g.Teams[taker].TeamNumber, won)
+}
+
+// targetTotals returns the totals of the given teams as they count toward the
+// points goal. Assumes lock is held.
+func (g *Game) targetTotals(teams []*shared.Team) []int {
+	totals := make([]int, len(teams))
+	for i, team := range teams {
+		totals[i] = team.TotalScore
+	}
+	if !g.excludeDeclarations {
+		return totals
+	}
+
+	// Declared points still count for the score, but a team whose total
+	// without them is short of the goal is kept just below it.
+	for i, team := range teams {
+		declared := 0
+		for _, sheet := range g.scoreSheet {
+			for _, line := range sheet.Teams {
+				if line.TeamNumber == team.TeamNumber {
+					declared += line.DeclarationPoints / 3
+				}
+			}
+		}
+		if totals[i] >= g.TargetScore && totals[i]-declared < g.TargetScore {
+			totals[i] = g.TargetScore - 1
+		}
+	}
+	return totals
+}
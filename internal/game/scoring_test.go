@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"tressette-game/internal/shared"
+)
+
+func TestRoundThirds(t *testing.T) {
+	tests := []struct {
+		name         string
+		players      int
+		rounding     RoundingPolicy
+		carried      []int // Thirds carried in from the last round
+		scores       []int // Team scores in thirds before rounding
+		lastTrick    int   // Seat that took the last trick
+		want         []int // Team scores in thirds after rounding
+		wantLost     []int
+		wantReceived []int
+		wantCarried  []int
+	}{
+		{
+			name:         "truncate drops the leftover thirds",
+			players:      TwoPlayers,
+			rounding:     RoundTruncate,
+			scores:       []int{20, 15},
+			want:         []int{18, 15},
+			wantLost:     []int{2, 0},
+			wantReceived: []int{0, 0},
+			wantCarried:  []int{0, 0},
+		},
+		{
+			name:         "carry keeps the leftover thirds for the next round",
+			players:      FourPlayers,
+			rounding:     RoundCarry,
+			scores:       []int{19, 16},
+			want:         []int{18, 15},
+			wantLost:     []int{0, 0},
+			wantReceived: []int{0, 0},
+			wantCarried:  []int{1, 1},
+		},
+		{
+			name:         "carry adds the thirds carried in first",
+			players:      TwoPlayers,
+			rounding:     RoundCarry,
+			carried:      []int{1, 1},
+			scores:       []int{17, 18},
+			want:         []int{18, 18},
+			wantLost:     []int{0, 0},
+			wantReceived: []int{0, 0},
+			wantCarried:  []int{0, 1},
+		},
+		{
+			name:         "last trick wins a point for the 2 leftover thirds of one team",
+			players:      TwoPlayers,
+			rounding:     RoundLastTrick,
+			scores:       []int{20, 15},
+			lastTrick:    1,
+			want:         []int{18, 18},
+			wantLost:     []int{0, 0},
+			wantReceived: []int{0, 3},
+			wantCarried:  []int{0, 0},
+		},
+		{
+			name:         "last trick pools a third from each team",
+			players:      FourPlayers,
+			rounding:     RoundLastTrick,
+			scores:       []int{19, 16},
+			lastTrick:    2,
+			want:         []int{21, 15},
+			wantLost:     []int{0, 0},
+			wantReceived: []int{3, 0},
+			wantCarried:  []int{0, 0},
+		},
+		{
+			name:         "terziglio truncates every player",
+			players:      ThreePlayers,
+			rounding:     RoundTruncate,
+			scores:       []int{10, 11, 14},
+			want:         []int{9, 9, 12},
+			wantLost:     []int{1, 2, 2},
+			wantReceived: []int{0, 0, 0},
+			wantCarried:  []int{0, 0, 0},
+		},
+		{
+			name:         "terziglio carries every player's thirds",
+			players:      ThreePlayers,
+			rounding:     RoundCarry,
+			scores:       []int{10, 11, 14},
+			want:         []int{9, 9, 12},
+			wantLost:     []int{0, 0, 0},
+			wantReceived: []int{0, 0, 0},
+			wantCarried:  []int{1, 2, 2},
+		},
+		{
+			name:         "terziglio last trick rounds the pool up",
+			players:      ThreePlayers,
+			rounding:     RoundLastTrick,
+			scores:       []int{10, 11, 14},
+			lastTrick:    2,
+			want:         []int{9, 9, 18},
+			wantLost:     []int{0, 0, 0},
+			wantReceived: []int{0, 0, 6},
+			wantCarried:  []int{0, 0, 0},
+		},
+		{
+			name:         "negative scores are left alone",
+			players:      TwoPlayers,
+			rounding:     RoundLastTrick,
+			scores:       []int{-4, 39},
+			lastTrick:    0,
+			want:         []int{-4, 39},
+			wantLost:     []int{0, 0},
+			wantReceived: []int{0, 0},
+			wantCarried:  []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := make([]*shared.Player, tt.players)
+			for i := range players {
+				players[i] = shared.NewPlayer(fmt.Sprintf("p%d", i), fmt.Sprintf("Player %d", i), 0)
+			}
+			g := NewGame(players, Settings{
+				TargetScore: 100,
+				Rules:       mustRuleset(t, "classic"),
+				Rounding:    tt.rounding,
+			}, nil)
+			g.newRoundSheet()
+			if tt.carried != nil {
+				g.carriedThirds = append([]int{}, tt.carried...)
+			}
+			for i, team := range g.Teams {
+				team.AddScore(tt.scores[i])
+			}
+			g.LastTrickWinnerIndex = tt.lastTrick
+
+			g.carryThirdsIn()
+			g.roundThirds()
+
+			var got, lost, received []int
+			for i, team := range g.Teams {
+				got = append(got, team.Score)
+				lost = append(lost, g.roundSheet[i].ThirdsLost)
+				received = append(received, g.roundSheet[i].ThirdsReceived)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scores = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(lost, tt.wantLost) {
+				t.Errorf("thirds lost = %v, want %v", lost, tt.wantLost)
+			}
+			if !reflect.DeepEqual(received, tt.wantReceived) {
+				t.Errorf("thirds received = %v, want %v", received, tt.wantReceived)
+			}
+			if !reflect.DeepEqual(g.carriedThirds, tt.wantCarried) {
+				t.Errorf("thirds carried = %v, want %v", g.carriedThirds, tt.wantCarried)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package game
+
+import "time"
+
+// MortoRule decides what happens to the card left over in a three-player deal.
+type MortoRule string
+
+const (
+	MortoAside     MortoRule = "aside"      // The morto is set aside and nobody scores it
+	MortoLastTrick MortoRule = "last_trick" // The morto's points go to whoever takes the last trick
+)
+
+// Mode decides whether players try to take points or to avoid them.
+type Mode string
+
+const (
+	ModeClassic  Mode = "classic"    // The first team to reach the points goal wins
+	ModeMisere   Mode = "a_perdere"  // Everyone plays alone; reaching the points goal knocks you out
+	ModeChiamare Mode = "a_chiamare" // Four players; partners are found by calling a card each round
+)
+
+// RoundingPolicy decides what happens to the thirds left over when a round's
+// score is rounded down to whole points.
+type RoundingPolicy string
+
+const (
+	RoundTruncate  RoundingPolicy = "truncate"   // Leftover thirds are dropped every round
+	RoundCarry     RoundingPolicy = "carry"      // Leftover thirds are carried into the next round
+	RoundLastTrick RoundingPolicy = "last_trick" // Every team's leftover thirds go to whoever took the last trick, rounded up to a point
+)
+
+// MaxCappottoBonus is the largest bonus a table can give for a cappotto.
+const MaxCappottoBonus = 11
+
+// TerziglioCardsPerPlayer is the hand size in the three-player variant (one card is left over).
+const TerziglioCardsPerPlayer = 13
+
+// Settings are the table options chosen when the lobby was created.
+type Settings struct {
+	TargetScore int       // Points needed to win the game
+	MortoRule   MortoRule // Only used by three-player games
+	Rules       Ruleset   // Variant being played, the classic rules if nil
+
+	Rounding            RoundingPolicy // How leftover thirds are handled, RoundTruncate if empty
+	CappottoBonus       int            // Classic mode only: extra points for taking every point of a round
+	ExcludeDeclarations bool           // Declarations score but don't count toward the points goal
+	Ranked              bool           // Two-sided games between registered players only: the result moves their ratings
+
+	DeclarationTimeout time.Duration // How long the declaration phase lasts, DefaultDeclarationTimeout if zero
+	SpectatorHandDelay time.Duration // How long before spectators see every hand; zero keeps hands hidden
+	Silent             bool          // No busso, striscio or volo at this table
+	TurnTimeout        time.Duration // Time allowed per move before the time bank is used; zero for no limit
+	TimeBank           time.Duration // Extra time per player for the whole game; zero for none
+	TrickPause         time.Duration // How long a finished trick stays on the table; zero for no pause
+	ReadyTimeout       time.Duration // How long to wait for everyone to be ready after a round; zero skips the ready check
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"log"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// checkSignal reports why a signal can't go with a card, or "" if it can.
+// Only the player leading a trick may signal, and striscio and volo must be
+// true of the hand: more cards of the suit remain, or none do. Assumes lock is held.
+func (g *Game) checkSignal(hand []shared.Card, card shared.Card, signal protocol.Signal) string {
+	if signal == "" {
+		return ""
+	}
+	if !g.signalsAllowed {
+		return "Signals are not allowed at this table."
+	}
+	if len(g.CurrentTrick.Cards) > 0 {
+		return "Only the player leading a trick can signal."
+	}
+
+	remaining := 0
+	for _, c := range hand {
+		if c.Suit == card.Suit && c != card {
+			remaining++
+		}
+	}
+	switch signal {
+	case protocol.SignalBusso:
+		return ""
+	case protocol.SignalStriscio:
+		if remaining == 0 {
+			return "Striscio means you hold more cards of the suit."
+		}
+		return ""
+	case protocol.SignalVolo:
+		if remaining > 0 {
+			return "Volo means this is your last card of the suit."
+		}
+		return ""
+	default:
+		return "Unknown signal."
+	}
+}
+
+// recordSignal remembers the signal given with the card leading the current trick. Assumes lock is held.
+func (g *Game) recordSignal(playerIndex int, card shared.Card, signal protocol.Signal) {
+	g.leadSignal = signal
+	g.leadSignalSeat = playerIndex
+	if signal == "" {
+		return
+	}
+	record := protocol.SignalRecord{
+		Round:    g.round,
+		Trick:    g.tricksPlayed + 1,
+		PlayerID: g.Players[playerIndex].ID,
+		Card:     card,
+		Signal:   signal,
+	}
+	g.signalHistory = append(g.signalHistory, record)
+	log.Printf("Game %s: Player %d (%s) leads %s %s with %s.", g.ID, playerIndex, g.Players[playerIndex].Name, card.Rank, card.Suit, signal)
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// MaxSpectatorHandDelay caps the delay a host can pick for revealing hands to spectators.
+const MaxSpectatorHandDelay = 10 * time.Minute
+
+// AddSpectator lets a client watch the game. Spectators receive every broadcast
+// (game_start, game_state_update, trick_end, round_end, game_over, ...) but never
+// a hand; they start with a snapshot of the table. Returns false if the game is over.
+func (g *Game) AddSpectator(clientID string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.GameState == GameOver {
+		log.Printf("Game %s: Client %s tried to spectate, but game already over.", g.ID, clientID)
+		return false
+	}
+	g.spectators[clientID] = true
+	log.Printf("Game %s: Client %s is now spectating (%d watching).", g.ID, clientID, len(g.spectators))
+
+	if g.sendMessage == nil {
+		return true // The game loop hasn't started; game_start will reach the spectator
+	}
+	snapshotMsg, _ := protocol.NewMessage("spectate_start", g.snapshot(-1))
+	g.sendMessage(clientID, snapshotMsg)
+	return true
+}
+
+// RemoveSpectator stops sending the game to a client that left.
+func (g *Game) RemoveSpectator(clientID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.spectators, clientID)
+	log.Printf("Game %s: Spectator %s left (%d watching).", g.ID, clientID, len(g.spectators))
+}
+
+// scheduleHandReveal shows spectators the hands as they are now once the host's
+// delay has passed, so nobody watching can pass information to a player. Only
+// one reveal is queued per state change (per event logged), it goes to the
+// spectators who were watching when it was queued, and it is dropped if the game
+// is over by then. Does nothing if the host keeps hands hidden. Assumes lock is held.
+func (g *Game) scheduleHandReveal() {
+	if g.spectatorHandDelay <= 0 || len(g.spectators) == 0 || g.GameState == GameOver {
+		return
+	}
+	if g.handsRevealAt == len(g.events) {
+		return // These hands are already queued
+	}
+	g.handsRevealAt = len(g.events)
+
+	payload := protocol.SpectatorHandsPayload{Hands: make(map[string][]shared.Card)}
+	for _, p := range g.Players {
+		hand := make([]shared.Card, len(p.Hand))
+		copy(hand, p.Hand)
+		payload.Hands[p.ID] = hand
+	}
+	handsMsg, _ := protocol.NewMessage("spectator_hands", payload)
+	watching := make([]string, 0, len(g.spectators))
+	for spectatorID := range g.spectators {
+		watching = append(watching, spectatorID)
+	}
+
+	time.AfterFunc(g.spectatorHandDelay, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.GameState == GameOver {
+			return
+		}
+		for _, spectatorID := range watching {
+			if g.spectators[spectatorID] {
+				g.sendMessage(spectatorID, handsMsg)
+			}
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// recorder counts the messages of each type sent to each client.
+type recorder struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int
+}
+
+func (r *recorder) send(clientID string, message []byte) {
+	var msg protocol.Message
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts[clientID] == nil {
+		r.counts[clientID] = make(map[string]int)
+	}
+	r.counts[clientID][msg.Type]++
+}
+
+func (r *recorder) count(clientID, msgType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[clientID][msgType]
+}
+
+func TestScheduleHandReveal(t *testing.T) {
+	const delay = 10 * time.Millisecond
+
+	tests := []struct {
+		name string
+		// after runs with the lock held once the first reveal is queued
+		after func(g *Game)
+		want  map[string]int // spectator_hands received, by spectator
+	}{
+		{
+			name:  "one reveal for one state",
+			after: func(g *Game) {},
+			want:  map[string]int{"s1": 1},
+		},
+		{
+			name:  "repeated updates of the same state queue no more",
+			after: func(g *Game) { g.broadcastGameState(); g.broadcastGameState() },
+			want:  map[string]int{"s1": 1},
+		},
+		{
+			name:  "a new state queues its own reveal",
+			after: func(g *Game) { g.logEvent(Event{Type: EventTimeout, Seat: 0}); g.broadcastGameState() },
+			want:  map[string]int{"s1": 2},
+		},
+		{
+			name:  "late spectators don't get earlier hands",
+			after: func(g *Game) { g.spectators["s2"] = true },
+			want:  map[string]int{"s1": 1, "s2": 0},
+		},
+		{
+			name:  "spectators who left get nothing",
+			after: func(g *Game) { delete(g.spectators, "s1") },
+			want:  map[string]int{"s1": 0},
+		},
+		{
+			name:  "dropped once the game is over",
+			after: func(g *Game) { g.GameState = GameOver },
+			want:  map[string]int{"s1": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := make([]*shared.Player, TwoPlayers)
+			for i := range players {
+				players[i] = shared.NewPlayer(fmt.Sprintf("p%d", i), fmt.Sprintf("Player %d", i), 0)
+			}
+			g := NewGame(players, Settings{
+				TargetScore:        100,
+				Rules:              mustRuleset(t, "no_declarations"),
+				SpectatorHandDelay: delay,
+			}, nil)
+			g.AddSpectator("s1")
+			rec := &recorder{counts: make(map[string]map[string]int)}
+			g.StartGameLoop(rec.send) // Queues the reveal of the dealt hands
+
+			g.mu.Lock()
+			tt.after(g)
+			g.mu.Unlock()
+
+			time.Sleep(delay + 30*time.Millisecond)
+			for spectatorID, want := range tt.want {
+				if got := rec.count(spectatorID, "spectator_hands"); got != want {
+					t.Errorf("%s got %d spectator_hands, want %d", spectatorID, got, want)
+				}
+			}
+		})
+	}
+}
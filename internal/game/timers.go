@@ -0,0 +1,155 @@
+package game
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+)
+
+// Limits on the turn clock chosen in create_game.
+const (
+	MaxTurnTimeout = 5 * time.Minute
+	MaxTimeBank    = time.Hour
+)
+
+// A player's clock runs while it is their turn to play a card or, in "a chiamare",
+// to call a partner. Each move may take up to the turn timeout, after which the
+// player's time bank is used up; with no turn timeout the bank works as a chess
+// clock and every second counts. Once both run out the server plays the lowest
+// legal card for them, or calls the lowest card they don't hold.
+
+// startTurnClock starts the clock of the player whose turn it is. Assumes lock is held.
+func (g *Game) startTurnClock() {
+	g.turnClock++
+	g.turnDeadline = time.Time{}
+	if g.turnTimeout <= 0 && g.timeBank <= 0 {
+		return
+	}
+
+	seat := g.PlayerTurnIndex
+	clock := g.turnClock
+	g.turnStart = time.Now()
+	g.turnDeadline = g.turnStart.Add(g.turnTimeout + g.timeBanks[seat])
+
+	time.AfterFunc(time.Until(g.turnDeadline), func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.turnClock != clock || len(g.disconnected) > 0 {
+			return
+		}
+		switch g.GameState {
+		case Playing:
+			g.handleTurnTimeout(seat)
+		case Calling:
+			g.handleCallTimeout(seat)
+		}
+	})
+}
+
+// stopTurnClock stops the running clock and charges the time past the turn
+// timeout to the player's bank. Assumes lock is held.
+func (g *Game) stopTurnClock() {
+	if g.turnDeadline.IsZero() {
+		return
+	}
+	seat := g.PlayerTurnIndex
+	if over := time.Since(g.turnStart) - g.turnTimeout; over > 0 && g.timeBank > 0 {
+		g.timeBanks[seat] = max(g.timeBanks[seat]-over, 0)
+	}
+	g.turnClock++
+	g.turnDeadline = time.Time{}
+}
+
+// handleTurnTimeout plays the lowest legal card for a player who ran out of time.
+// Assumes lock is held.
+func (g *Game) handleTurnTimeout(seat int) {
+	player := g.Players[seat]
+	moves := ValidMoves(g.Rules, player.Hand, g.trickState())
+	if len(moves) == 0 {
+		return
+	}
+	card := lowestOrder(moves)
+
+	g.stopTurnClock()
+	g.timeouts[seat]++
+	g.logEvent(Event{Type: EventTimeout, Seat: seat})
+	if g.timeouts[seat] > 1 {
+		log.Printf("Game %s: Player %d (%s) timed out again (%d times), playing %s %s.", g.ID, seat, player.Name, g.timeouts[seat], card.Rank, card.Suit)
+	} else {
+		log.Printf("Game %s: Player %d (%s) timed out, playing %s %s.", g.ID, seat, player.Name, card.Rank, card.Suit)
+	}
+
+	payload := protocol.TurnTimeoutPayload{
+		PlayerID: player.ID,
+		Card:     card,
+		Timeouts: g.timeouts[seat],
+	}
+	msg, _ := protocol.NewMessage("turn_timeout", payload)
+	g.broadcast(msg)
+
+	g.playCard(seat, card, "")
+}
+
+// handleCallTimeout calls the lowest card the caller doesn't hold for a caller who
+// ran out of time. Assumes lock is held.
+func (g *Game) handleCallTimeout(seat int) {
+	player := g.Players[seat]
+	calls := []shared.Card{}
+	for _, c := range shared.NewDeck().Cards {
+		if _, held := player.FindCard(c.Suit, c.Rank); !held {
+			calls = append(calls, c)
+		}
+	}
+	card := lowestOrder(calls)
+
+	g.timeouts[seat]++
+	g.logEvent(Event{Type: EventTimeout, Seat: seat})
+	log.Printf("Game %s: Player %d (%s) timed out calling a partner, calling %s %s.", g.ID, seat, player.Name, card.Rank, card.Suit)
+
+	payload := protocol.TurnTimeoutPayload{
+		PlayerID: player.ID,
+		Card:     card,
+		Called:   true,
+		Timeouts: g.timeouts[seat],
+	}
+	msg, _ := protocol.NewMessage("turn_timeout", payload)
+	g.broadcast(msg)
+
+	g.handleCall(seat, protocol.CallCardPayload{Suit: card.Suit, Rank: card.Rank})
+}
+
+// turnSecondsLeft returns how long the current player has left to play, or 0 when
+// no clock is running. Assumes lock is held.
+func (g *Game) turnSecondsLeft() int {
+	if g.turnDeadline.IsZero() {
+		return 0
+	}
+	return max(int(math.Ceil(time.Until(g.turnDeadline).Seconds())), 0)
+}
+
+// timeBankSeconds lists every player's remaining time bank, or nil when the table
+// has none. Assumes lock is held.
+func (g *Game) timeBankSeconds() map[string]int {
+	if g.timeBank <= 0 {
+		return nil
+	}
+	banks := make(map[string]int, len(g.Players))
+	for i, p := range g.Players {
+		banks[p.ID] = int(g.timeBanks[i].Seconds())
+	}
+	return banks
+}
+
+// timeoutCounts lists how often each player has run out of time. Assumes lock is held.
+func (g *Game) timeoutCounts() map[string]int {
+	counts := make(map[string]int)
+	for i, n := range g.timeouts {
+		if n > 0 {
+			counts[g.Players[i].ID] = n
+		}
+	}
+	return counts
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"tressette-game/internal/shared"
+)
+
+func TestCallTimeout(t *testing.T) {
+	players := make([]*shared.Player, FourPlayers)
+	for i := range players {
+		players[i] = shared.NewPlayer(fmt.Sprintf("p%d", i), fmt.Sprintf("Player %d", i), 0)
+	}
+	g := NewGame(players, Settings{
+		TargetScore:  100,
+		Rules:        mustRuleset(t, string(ModeChiamare)),
+		TurnTimeout:  10 * time.Millisecond,
+		ReadyTimeout: time.Hour,
+	}, nil)
+	g.StartGameLoop(func(string, []byte) {})
+
+	g.mu.Lock()
+	caller := g.Players[g.callerIndex]
+	hand := append([]shared.Card{}, caller.Hand...)
+	g.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		g.mu.Lock()
+		state := g.GameState
+		g.mu.Unlock()
+		if state != Calling {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the caller's clock never ran out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calledCard == nil {
+		t.Fatalf("no card was called")
+	}
+	lowest := 0
+	for _, c := range shared.NewDeck().Cards {
+		if !slices.Contains(hand, c) && (lowest == 0 || c.Order < lowest) {
+			lowest = c.Order
+		}
+	}
+	if slices.Contains(hand, *g.calledCard) || g.calledCard.Order != lowest {
+		t.Errorf("called %s %s, want the lowest card the caller doesn't hold", g.calledCard.Rank, g.calledCard.Suit)
+	}
+	if got := g.timeouts[g.callerIndex]; got != 1 {
+		t.Errorf("caller timeouts = %d, want 1", got)
+	}
+}
@@ -24,17 +24,105 @@ type CreateGamePayload struct {
 	Name        string          `json:"name"`
 	DesiredTeam shared.TeamEnum `json:"desired_team"` // Added desired team
 	PointsGoal  int             `json:"points_goal"`  // Added points goal
+	PlayerCount int             `json:"player_count"` // 2 (heads-up with stock), 3 (Terziglio) or 4; defaults to 4
+	MortoRule   string          `json:"morto_rule"`   // Three players only: "aside" (default) or "last_trick"
+	Ruleset     string          `json:"ruleset"`      // Named variant, see /api/rulesets; defaults to "classic"
+
+	SpectatorHandDelay int  `json:"spectator_hand_delay"` // Seconds before spectators see every hand; 0 keeps hands hidden
+	Silent             bool `json:"silent"`               // Silent table: no busso, striscio or volo
+	TurnTimeout        int  `json:"turn_timeout"`         // Seconds allowed per move; 0 for no limit
+	TimeBank           int  `json:"time_bank"`            // Seconds of extra time per player for the whole game; 0 for none
+
+	Rounding            string `json:"rounding"`             // Leftover thirds: "truncate" (default), "carry" or "last_trick"
+	CappottoBonus       int    `json:"cappotto_bonus"`       // Classic mode only: extra points for taking every point of a round
+	ExcludeDeclarations bool   `json:"exclude_declarations"` // Declarations score but don't count toward the points goal
+
+	BestOf int  `json:"best_of"` // Games in the match: 1 (default), 3 or 5
+	Public bool `json:"public"`  // Listed in the lobby browser; private lobbies are joined by code only
+	Ranked bool `json:"ranked"`  // Counts toward the ratings; registered players only, two sides
 }
 
 type JoinGamePayload struct {
 	Name        string          `json:"name"`
-	GameCode    string          `json:"game_code"`    // Added game code
-	DesiredTeam shared.TeamEnum `json:"desired_team"` // Added desired team
+	GameCode    string          `json:"game_code"`            // Added game code
+	DesiredTeam shared.TeamEnum `json:"desired_team"`         // Added desired team
+	SeatToken   string          `json:"seat_token,omitempty"` // Join token of the seat kept at a tournament table
+}
+
+// QuickMatchPayload puts the player in the queue for the next quick-match table.
+type QuickMatchPayload struct {
+	Name string `json:"name"`
+}
+
+type RejoinGamePayload struct {
+	Token string `json:"token"` // Session token issued on create_game/join_game
+}
+
+type AddBotPayload struct {
+	Team shared.TeamEnum `json:"team"` // Team the bot should play for
+}
+
+type RemoveBotPayload struct {
+	BotID string `json:"bot_id"`
+}
+
+type ChooseSeatPayload struct {
+	Seat int `json:"seat"` // Free seat to move to, starting at 0
+}
+
+// SwapRequestPayload asks the player in a seat to trade places. The swap happens
+// once they ask for the sender's seat too; bots swap right away.
+type SwapRequestPayload struct {
+	Seat int `json:"seat"`
 }
 
+type LockSeatsPayload struct {
+	Locked bool `json:"locked"`
+}
+
+// Signal is a traditional hint the player leading a trick may give with their card.
+type Signal string
+
+const (
+	SignalBusso    Signal = "busso"    // Knock: play your best card of this suit back to me
+	SignalStriscio Signal = "striscio" // Slide: I hold more cards of this suit
+	SignalVolo     Signal = "volo"     // Fly: this is my last card of this suit
+)
+
 type PlayCardPayload struct {
-	Suit shared.Suit `json:"suit"`
-	Rank string      `json:"rank"`
+	Suit   shared.Suit `json:"suit"`
+	Rank   string      `json:"rank"`
+	Signal Signal      `json:"signal,omitempty"` // Only when leading a trick at a table that allows signals
+}
+
+// SpectateGamePayload asks to watch a running game without taking a seat.
+type SpectateGamePayload struct {
+	GameCode string `json:"game_code"`
+	Name     string `json:"name"`
+}
+
+// SpectatorHandsPayload shows spectators every hand as it was a while ago (see CreateGamePayload.SpectatorHandDelay).
+type SpectatorHandsPayload struct {
+	Hands map[string][]shared.Card `json:"hands"` // Keyed by player ID
+}
+
+// Chat channels. Spectators can only use and read ChatChannelSpectators, which players never see.
+const (
+	ChatChannelAll        = "all"        // Everyone seated in the lobby or game
+	ChatChannelTeam       = "team"       // The sender's team, once the game has started
+	ChatChannelSpectators = "spectators" // Spectators of the game
+)
+
+// ChatPayload posts a chat message to a channel of the sender's lobby or game.
+type ChatPayload struct {
+	Channel string `json:"channel"` // Defaults to "all"
+	Text    string `json:"text"`
+}
+
+// MutePlayerPayload is sent by the host to mute or unmute someone, and broadcast as player_muted.
+type MutePlayerPayload struct {
+	PlayerID string `json:"player_id"`
+	Muted    bool   `json:"muted"`
 }
 
 type DeclarePayload struct {
@@ -43,6 +131,23 @@ type DeclarePayload struct {
 	Rank            string      `json:"rank"`
 }
 
+// CallCardPayload names the card whose holder becomes the caller's secret partner
+// ("a chiamare"). A caller holding all four 3s may instead set Alone and play by themself.
+type CallCardPayload struct {
+	Suit  shared.Suit `json:"suit"`
+	Rank  string      `json:"rank"`
+	Alone bool        `json:"alone"`
+}
+
+// DeclarationPhasePayload is broadcast when the declaration phase opens and whenever a player finishes declaring.
+type DeclarationPhasePayload struct {
+	PendingPlayerIDs []string `json:"pending_player_ids"` // Players who have not confirmed they are done
+	SecondsLeft      int      `json:"seconds_left"`       // Time until play starts regardless
+
+	// Declarations the recipient can still make; only filled in for players who are still declaring
+	AvailableDeclarations []DeclarePayload `json:"available_declarations,omitempty"`
+}
+
 type DeclarationConfirmationPayload struct {
 	TeamID      string         `json:"team_id"`      // ID of the team making the declaration
 	PlayerID    string         `json:"player_id"`    // ID of the player making the declaration
@@ -58,7 +163,78 @@ type GameCreatedPayload struct {
 }
 
 type LobbyUpdatePayload struct {
-	Players []PlayerInfo `json:"players"`
+	Players     []PlayerInfo `json:"players"`
+	PlayerCount int          `json:"player_count"` // Seats needed to start the game
+	Seats       []LobbySeat  `json:"seats"`        // Every seat at the table, in playing order
+	HostID      string       `json:"host_id"`
+	SeatsLocked bool         `json:"seats_locked"`
+}
+
+// LobbySeat is a seat at a table that is still gathering players.
+type LobbySeat struct {
+	Seat     int             `json:"seat"`
+	Team     shared.TeamEnum `json:"team"`
+	PlayerID string          `json:"player_id,omitempty"` // Empty for a free seat
+}
+
+// LobbyListing describes a public lobby in the lobby browser.
+type LobbyListing struct {
+	GameCode    string      `json:"game_code"`
+	Host        string      `json:"host"` // Name of the host
+	Ruleset     string      `json:"ruleset"`
+	Mode        string      `json:"mode"`
+	PlayerCount int         `json:"player_count"`
+	PointsGoal  int         `json:"points_goal"`
+	BestOf      int         `json:"best_of"`
+	TurnTimeout int         `json:"turn_timeout"` // Seconds per move, 0 for no limit
+	TimeBank    int         `json:"time_bank"`    // Seconds of extra time per player, 0 for none
+	Silent      bool        `json:"silent"`
+	SeatsLocked bool        `json:"seats_locked"`
+	Ranked      bool        `json:"ranked"`
+	FreeSeats   []LobbySeat `json:"free_seats"`
+}
+
+type LobbyListPayload struct {
+	Lobbies []LobbyListing `json:"lobbies"`
+}
+
+// QueueUpdatePayload tells a player where they stand in the quick-match queue.
+type QueueUpdatePayload struct {
+	Queued  bool `json:"queued"`  // False once the player left the queue
+	Waiting int  `json:"waiting"` // Players waiting in the queue, the player included
+}
+
+// QuickMatchFoundPayload tells a queued player the table they were seated at.
+type QuickMatchFoundPayload struct {
+	GameCode string `json:"game_code"`
+}
+
+// SwapRequestedPayload tells a player that someone wants to trade seats with them.
+type SwapRequestedPayload struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Seat     int    `json:"seat"` // Seat the player asking sits in
+}
+
+type SessionPayload struct {
+	Token    string `json:"token"`     // Token to present in rejoin_game after a disconnect
+	PlayerID string `json:"player_id"` // Player ID bound to the session
+	GameCode string `json:"game_code"`
+}
+
+// ChatMessagePayload is a chat message delivered to everyone allowed to read its channel.
+type ChatMessagePayload struct {
+	Channel    string `json:"channel"`
+	SenderID   string `json:"sender_id"`
+	SenderName string `json:"sender_name"`
+	Text       string `json:"text"`
+	SentAt     string `json:"sent_at"`               // RFC 3339
+	TeamNumber int    `json:"team_number,omitempty"` // Team channel only
+}
+
+// ChatHistoryPayload gives someone joining a lobby or game the recent messages they may read.
+type ChatHistoryPayload struct {
+	Messages []ChatMessagePayload `json:"messages"`
 }
 
 type JoinErrorPayload struct {
@@ -70,9 +246,11 @@ type GameWaitPayload struct {
 }
 
 type PlayerInfo struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Position int    `json:"position"` // Player's position in the game (0-3)
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Position int             `json:"position"`       // Player's seat in the game, starting at 0
+	Team     shared.TeamEnum `json:"team,omitempty"` // Team the seat plays for, set in the lobby
+	IsBot    bool            `json:"is_bot,omitempty"`
 }
 
 type TeamInfo struct {
@@ -83,29 +261,85 @@ type TeamInfo struct {
 }
 
 type GameStartPayload struct {
-	GameID     string       `json:"game_id"`
-	Players    []PlayerInfo `json:"players"`
-	Teams      []TeamInfo   `json:"teams"`
-	PointsGoal int          `json:"points_goal"` // Added points goal
+	GameID       string       `json:"game_id"`
+	Players      []PlayerInfo `json:"players"`
+	Teams        []TeamInfo   `json:"teams"`
+	PointsGoal   int          `json:"points_goal"`  // Added points goal
+	Mode         string       `json:"mode"`         // "classic", "a_perdere" or "a_chiamare"
+	Ruleset      string       `json:"ruleset"`      // Named variant being played
+	Declarations bool         `json:"declarations"` // Whether declarations are allowed
+	Signals      bool         `json:"signals"`      // Whether busso, striscio and volo may be used
+
+	Rounding            string `json:"rounding"`             // "truncate", "carry" or "last_trick"
+	CappottoBonus       int    `json:"cappotto_bonus"`       // Extra points for taking every point of a round
+	ExcludeDeclarations bool   `json:"exclude_declarations"` // Whether declarations are left out of the points goal
+	Ranked              bool   `json:"ranked"`               // Whether the result moves the players' ratings
+}
+
+// ReadyCheckPayload is broadcast after a round until everyone is ready for the next one.
+type ReadyCheckPayload struct {
+	PendingPlayerIDs []string `json:"pending_player_ids"` // Players who have not sent ready yet
+	SecondsLeft      int      `json:"seconds_left"`       // Time before the next round starts anyway
+}
+
+// ShuffleCommitmentPayload is broadcast before every deal. The seed it commits to
+// is revealed at round_end, so anyone can check SHA-256(seed) against it.
+type ShuffleCommitmentPayload struct {
+	Round      int    `json:"round"`
+	Commitment string `json:"commitment"` // Hex SHA-256 of the round's shuffle seed
 }
 
 type DealHandPayload struct {
-	Hand []shared.Card `json:"hand"`
+	Hand  []shared.Card `json:"hand"`
+	Trump *shared.Card  `json:"trump,omitempty"` // Card turned up as trump (Briscola)
 }
 
 type YourTurnPayload struct {
-	PlayerID   string        `json:"player_id"`
-	ValidMoves []shared.Card `json:"valid_moves,omitempty"`
+	PlayerID        string        `json:"player_id"`
+	ValidMoves      []shared.Card `json:"valid_moves,omitempty"`       // Cards the player may legally play
+	SecondsLeft     int           `json:"seconds_left,omitempty"`      // Time left for this move, bank included; 0 without a clock
+	TimeBankSeconds int           `json:"time_bank_seconds,omitempty"` // Player's remaining time bank
+}
+
+// TurnTimeoutPayload is broadcast when a player runs out of time and a card is played
+// (or, in "a chiamare", called) for them.
+type TurnTimeoutPayload struct {
+	PlayerID string      `json:"player_id"`
+	Card     shared.Card `json:"card"`             // Card played or called on the player's behalf
+	Called   bool        `json:"called,omitempty"` // Whether Card was called as the partner card rather than played
+	Timeouts int         `json:"timeouts"`         // How often the player has run out of time this game
 }
 
 type GameStatePayload struct {
-	CurrentPlayerID   string        `json:"current_player_id"`
-	CardsOnTable      []shared.Card `json:"cards_on_table"`
-	Team1Score        int           `json:"team1_score"`
-	Team2Score        int           `json:"team2_score"`
-	LastTrick         []shared.Card `json:"last_trick,omitempty"`
-	LastTrickWinnerID string        `json:"last_winner_id,omitempty"`
-	GameState         string        `json:"game_state"`
+	CurrentPlayerID   string         `json:"current_player_id"`
+	CardsOnTable      []shared.Card  `json:"cards_on_table"`
+	Team1Score        int            `json:"team1_score"`
+	Team2Score        int            `json:"team2_score"`
+	Scores            []int          `json:"scores"` // Round score of every team, ordered by team number
+	LastTrick         []shared.Card  `json:"last_trick,omitempty"`
+	LastTrickWinnerID string         `json:"last_winner_id,omitempty"`
+	StockRemaining    int            `json:"stock_remaining"` // Cards left to draw
+	Trump             *shared.Card   `json:"trump,omitempty"` // Card turned up as trump (Briscola)
+	GameState         string         `json:"game_state"`
+	Signal            Signal         `json:"signal,omitempty"`           // Signal given with the card leading the current trick
+	SignalPlayerID    string         `json:"signal_player_id,omitempty"` // Player who gave Signal
+	TurnSeconds       int            `json:"turn_seconds,omitempty"`     // Time the current player has left, 0 without a clock
+	TimeBanks         map[string]int `json:"time_banks,omitempty"`       // Remaining time bank of every player, in seconds
+}
+
+// SignalRecord is a signal given during the game, kept in its history.
+type SignalRecord struct {
+	Round    int         `json:"round"` // Starting from 1
+	Trick    int         `json:"trick"` // Trick of the round, starting from 1
+	PlayerID string      `json:"player_id"`
+	Card     shared.Card `json:"card"`
+	Signal   Signal      `json:"signal"`
+}
+
+type CardDrawnPayload struct {
+	PlayerID       string       `json:"player_id"`
+	Card           *shared.Card `json:"card,omitempty"` // Only sent to the other players if draws are public
+	StockRemaining int          `json:"stock_remaining"`
 }
 
 type TrickEndPayload struct {
@@ -115,17 +349,90 @@ type TrickEndPayload struct {
 	Points   int               `json:"points"`
 }
 
+// TeamRoundScore is one team's line of a round's score sheet. Points are in
+// thirds (scaled by 3) unless noted otherwise.
+type TeamRoundScore struct {
+	TeamNumber        int `json:"team_number"`
+	Aces              int `json:"aces"`               // Aces taken
+	Figures           int `json:"figures"`            // 2s, 3s and face cards taken, a third each
+	CardPoints        int `json:"card_points"`        // Value of all the cards taken
+	LastTrickBonus    int `json:"last_trick_bonus"`   // Bonus for taking the last trick
+	MortoPoints       int `json:"morto_points"`       // Three players only: the morto added to the last trick
+	DeclarationPoints int `json:"declaration_points"` // Declarations announced this round
+	Adjustment        int `json:"adjustment"`         // Change made when settling the round (cappotto, partnerships, ...)
+	CappottoBonus     int `json:"cappotto_bonus"`     // Bonus for taking every point of the round
+	ThirdsCarriedIn   int `json:"thirds_carried_in"`  // Leftover thirds carried over from the last round
+	ThirdsReceived    int `json:"thirds_received"`    // Leftover thirds won with the last trick, rounded up to whole points
+	ThirdsCarriedOut  int `json:"thirds_carried_out"` // Leftover thirds carried into the next round
+	ThirdsLost        int `json:"thirds_lost"`        // Thirds dropped when rounding down to whole points
+	RoundPoints       int `json:"round_points"`       // Whole points scored this round
+	TotalScore        int `json:"total_score"`        // Whole points after the round
+}
+
+// RoundScoreSheet is the score sheet of one round, with a line for every team.
+type RoundScoreSheet struct {
+	Round int              `json:"round"`
+	Teams []TeamRoundScore `json:"teams"` // Ordered by team number
+}
+
 type RoundEndPayload struct {
-	Team1RoundScore int `json:"team1_round_score"`
-	Team2RoundScore int `json:"team2_round_score"`
-	Team1TotalScore int `json:"team1_total_score"`
-	Team2TotalScore int `json:"team2_total_score"`
+	Team1RoundScore  int             `json:"team1_round_score"`
+	Team2RoundScore  int             `json:"team2_round_score"`
+	Team1TotalScore  int             `json:"team1_total_score"`
+	Team2TotalScore  int             `json:"team2_total_score"`
+	RoundScores      []int           `json:"round_scores"`                 // Every team, ordered by team number
+	TotalScores      []int           `json:"total_scores"`                 // Every team, ordered by team number
+	Morto            *shared.Card    `json:"morto,omitempty"`              // Card left over in a three-player deal
+	CappottoPlayerID string          `json:"cappotto_player_id,omitempty"` // "A perdere" only: player who took every point
+	Seed             string          `json:"seed"`                         // Hex shuffle seed of the round, now revealed
+	Commitment       string          `json:"commitment"`                   // SHA-256 of the seed, as published before the deal
+	ScoreSheet       RoundScoreSheet `json:"score_sheet"`                  // Breakdown of the round's scores
 }
 
 type GameOverPayload struct {
-	WinningTeamID string `json:"winning_team_id"`
-	FinalScoreT1  int    `json:"final_score_t1"`
-	FinalScoreT2  int    `json:"final_score_t2"`
+	WinningTeamID     string `json:"winning_team_id"`
+	WinningTeamNumber int    `json:"winning_team_number"`
+	FinalScoreT1      int    `json:"final_score_t1"`
+	FinalScoreT2      int    `json:"final_score_t2"`
+	FinalScores       []int  `json:"final_scores"` // Every team, ordered by team number
+}
+
+// RematchPayload answers the vote held after every game: accept to play the
+// next game of the match (or a new match) with the same seats, decline to leave the table.
+type RematchPayload struct {
+	Accept bool `json:"accept"`
+}
+
+// MatchUpdatePayload is broadcast after every game with the state of the series
+// and of the vote for the next game.
+type MatchUpdatePayload struct {
+	MatchID          string   `json:"match_id"`
+	BestOf           int      `json:"best_of"`
+	Wins             []int    `json:"wins"`               // Games won by every team, ordered by team number
+	GamesPlayed      int      `json:"games_played"`       // Games finished in the series
+	Tiebreak         bool     `json:"tiebreak"`           // The series is level after BestOf games and goes on until one team leads
+	Finished         bool     `json:"finished"`           // The series is decided; a rematch starts a new one
+	WinningTeam      int      `json:"winning_team"`       // Team number of the series winner, 0 until finished
+	PendingPlayerIDs []string `json:"pending_player_ids"` // Players who have not accepted the next game yet
+}
+
+// TableClosedPayload tells the players and spectators that the table broke up
+// and they are free to create or join another game.
+type TableClosedPayload struct {
+	PlayerID string `json:"player_id,omitempty"` // Player who declined the rematch or left, if any
+}
+
+// CardCalledPayload announces the caller's choice. Card is nil when the caller plays alone.
+type CardCalledPayload struct {
+	CallerID string       `json:"caller_id"`
+	Card     *shared.Card `json:"card,omitempty"`
+	Alone    bool         `json:"alone"`
+}
+
+// PartnerRevealedPayload is sent once the called card hits the table.
+type PartnerRevealedPayload struct {
+	CallerID  string `json:"caller_id"`
+	PartnerID string `json:"partner_id"`
 }
 
 type ErrorPayload struct {
@@ -136,6 +443,47 @@ type PlayerLeftPayload struct {
 	PlayerID string `json:"player_id"`
 }
 
+// PlayerEliminatedPayload announces that a player reached the points goal in an "a perdere" game.
+type PlayerEliminatedPayload struct {
+	PlayerID   string `json:"player_id"`
+	TotalScore int    `json:"total_score"`
+}
+
+type PlayerDisconnectedPayload struct {
+	PlayerID           string `json:"player_id"`
+	GracePeriodSeconds int    `json:"grace_period_seconds"` // Time the seat is held before the game is forfeited
+}
+
+type PlayerReconnectedPayload struct {
+	PlayerID string `json:"player_id"`
+}
+
+// GameSnapshotPayload carries everything a rejoining player needs to rebuild the table.
+type GameSnapshotPayload struct {
+	GameStartPayload
+	PlayerID        string                           `json:"player_id"` // ID the rejoining client is bound to
+	Hand            []shared.Card                    `json:"hand"`
+	CardsOnTable    []shared.Card                    `json:"cards_on_table"`
+	CurrentPlayerID string                           `json:"current_player_id"`
+	Team1Score      int                              `json:"team1_score"`
+	Team2Score      int                              `json:"team2_score"`
+	Team1TotalScore int                              `json:"team1_total_score"`
+	Team2TotalScore int                              `json:"team2_total_score"`
+	Scores          []int                            `json:"scores"`
+	TotalScores     []int                            `json:"total_scores"`
+	Declarations    []DeclarationConfirmationPayload `json:"declarations"` // Declarations made this round, in order
+	StockRemaining  int                              `json:"stock_remaining"`
+	Trump           *shared.Card                     `json:"trump,omitempty"`
+	GameState       string                           `json:"game_state"`
+	Eliminated      []string                         `json:"eliminated"`           // Players knocked out of an "a perdere" game
+	Call            *CardCalledPayload               `json:"call,omitempty"`       // "A chiamare" only: this round's call
+	PartnerID       string                           `json:"partner_id,omitempty"` // Caller's partner, once revealed (or to the partner)
+	SignalHistory   []SignalRecord                   `json:"signal_history"`       // Every signal given so far
+	Commitment      string                           `json:"commitment"`           // Shuffle commitment of the current round
+	Timeouts        map[string]int                   `json:"timeouts,omitempty"`   // How often each player ran out of time
+	ScoreSheet      []RoundScoreSheet                `json:"score_sheet"`          // Every finished round so far
+}
+
 type PlayerPlayedCardPayload struct {
 	PlayerID string      `json:"player_id"`
 	Card     shared.Card `json:"card"`
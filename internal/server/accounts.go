@@ -0,0 +1,208 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"tressette-game/internal/database"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Players may register an account and log in over REST. The login token is
+// then passed when opening the WebSocket, and the connection plays under the
+// account's username, so nobody else can use that name and the account's games
+// are linked to it rather than to a display name. Guests keep playing with a
+// name of their choice, as long as no account owns it.
+
+const MinPasswordLength = 8
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,20}$`)
+
+// AccountRequest is the body of a register or login request.
+type AccountRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse carries the token to present when opening the WebSocket.
+type LoginResponse struct {
+	Token     string `json:"token"`
+	AccountID string `json:"account_id"`
+	Username  string `json:"username"`
+}
+
+// RegisterHandler creates an account.
+func RegisterHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req AccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !usernamePattern.MatchString(req.Username) {
+		http.Error(w, "Username must be 3 to 20 letters, digits, dashes or underscores", http.StatusBadRequest)
+		return
+	}
+	if len(req.Password) < MinPasswordLength {
+		http.Error(w, "Password is too short", http.StatusBadRequest)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Failed to create account", http.StatusInternalServerError)
+		return
+	}
+	account := database.Account{
+		ID:           uuid.NewString(),
+		Username:     req.Username,
+		PasswordHash: string(hash),
+		CreatedAt:    time.Now().Format(time.RFC3339),
+	}
+	if err := db.InsertAccount(account); err != nil {
+		if errors.Is(err, database.ErrUsernameTaken) {
+			http.Error(w, "Username already taken", http.StatusConflict)
+			return
+		}
+		log.Printf("Error creating account %s: %v", req.Username, err)
+		http.Error(w, "Failed to create account", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Account %s (%s) registered.", account.ID, account.Username)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+// LoginHandler checks a username and password and returns a new login token.
+func LoginHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req AccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	account, err := db.GetAccountByUsername(req.Username)
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+	if err == sql.ErrNoRows || bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(req.Password)) != nil {
+		http.Error(w, "Wrong username or password", http.StatusUnauthorized)
+		return
+	}
+
+	token := uuid.NewString()
+	if err := db.InsertAuthToken(token, account.ID, time.Now().Format(time.RFC3339)); err != nil {
+		log.Printf("Error storing login token for account %s: %v", account.ID, err)
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{Token: token, AccountID: account.ID, Username: account.Username})
+}
+
+// LogoutHandler revokes the bearer token of the request.
+func LogoutHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := loginToken(r)
+	if token == "" {
+		http.Error(w, "Login token is required", http.StatusBadRequest)
+		return
+	}
+	if err := db.DeleteAuthToken(token); err != nil {
+		http.Error(w, "Failed to log out", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetResultsByAccountHandler returns the games a registered player played.
+func GetResultsByAccountHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	account, err := db.GetAccountByUsername(r.PathValue("username"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Account not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to fetch account", http.StatusInternalServerError)
+		return
+	}
+
+	results, err := db.GetByAccount(account.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "No results found for player", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to fetch results", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// loginToken returns the login token of a request, from the token query
+// parameter (browsers can't set headers on a WebSocket) or a bearer token.
+func loginToken(r *http.Request) string {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// authenticate returns the account a request logged in with. A request without
+// a token is a guest and gets an empty account; ok is false for an unknown token.
+func (h *Hub) authenticate(r *http.Request) (account database.Account, ok bool) {
+	token := loginToken(r)
+	if token == "" {
+		return database.Account{}, true
+	}
+	account, err := h.db.GetAccountByToken(token)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error checking login token: %v", err)
+		}
+		return database.Account{}, false
+	}
+	return account, true
+}
+
+// playerName returns the name a client plays under: its username once logged
+// in, otherwise the name it asked for, which must not belong to an account.
+func (h *Hub) playerName(client *Client, requested string) (string, error) {
+	if client.AccountID != "" {
+		return client.Name, nil // Set to the username when the connection logged in
+	}
+	if requested == "" {
+		return "", errors.New("Name cannot be empty.")
+	}
+	if _, err := h.db.GetAccountByUsername(requested); err == nil {
+		return "", errors.New("That name belongs to a registered player. Log in to use it.")
+	} else if err != sql.ErrNoRows {
+		log.Printf("Error looking up account %s: %v", requested, err)
+		return "", errors.New("Could not check the name, please try again.")
+	}
+	return requested, nil
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"tressette-game/internal/protocol"
+)
+
+// publicLobbies lists the public lobbies that still have a free seat, by game code.
+func (h *Hub) publicLobbies() []protocol.LobbyListing {
+	h.lobbyMu.RLock()
+	defer h.lobbyMu.RUnlock()
+
+	listings := []protocol.LobbyListing{}
+	for code, lobby := range h.lobbies {
+		if !lobby.Public || lobby.IsFull() {
+			continue
+		}
+		listing := protocol.LobbyListing{
+			GameCode:    code,
+			Ruleset:     lobby.Settings.Rules.Name(),
+			Mode:        string(lobby.Settings.Rules.Mode()),
+			PlayerCount: lobby.PlayerCount,
+			PointsGoal:  lobby.Settings.Rules.TargetScore(lobby.Settings.TargetScore),
+			BestOf:      lobby.BestOf,
+			TurnTimeout: int(lobby.Settings.TurnTimeout.Seconds()),
+			TimeBank:    int(lobby.Settings.TimeBank.Seconds()),
+			Silent:      lobby.Settings.Silent,
+			SeatsLocked: lobby.SeatsLocked,
+			Ranked:      lobby.Settings.Ranked,
+			FreeSeats:   []protocol.LobbySeat{},
+		}
+		if host := lobby.Host(); host != nil {
+			listing.Host = host.Name
+		}
+		for seat, c := range lobby.Seats {
+			if c == nil {
+				listing.FreeSeats = append(listing.FreeSeats, protocol.LobbySeat{Seat: seat, Team: lobby.SeatTeam(seat)})
+			}
+		}
+		listings = append(listings, listing)
+	}
+	sort.Slice(listings, func(i, j int) bool { return listings[i].GameCode < listings[j].GameCode })
+	return listings
+}
+
+// handleListLobbies sends the lobby browser to a client.
+func (h *Hub) handleListLobbies(client *Client) {
+	msg, _ := protocol.NewMessage("lobby_list", protocol.LobbyListPayload{Lobbies: h.publicLobbies()})
+	h.sendMessageToClient(client.ID, msg)
+}
+
+// GetLobbiesHandler returns the public lobbies that still have a free seat.
+func GetLobbiesHandler(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(protocol.LobbyListPayload{Lobbies: hub.publicLobbies()})
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"tressette-game/internal/protocol"
+)
+
+// Chat limits.
+const (
+	MaxChatLength   = 200              // Longest chat message, in characters
+	ChatHistorySize = 50               // Messages kept per lobby or game for late joiners
+	ChatRateLimit   = 5                // Messages a client may send per ChatRateWindow
+	ChatRateWindow  = 10 * time.Second // Window for ChatRateLimit
+)
+
+// chatRoom holds the chat of one lobby and, once it starts, of its game.
+type chatRoom struct {
+	hostID  string                        // Client that may mute others
+	history []protocol.ChatMessagePayload // Most recent messages, oldest first
+	muted   map[string]bool               // Clients that may not chat
+	sent    map[string][]time.Time        // Recent send times per client, for the rate limit
+}
+
+// chatRooms maps a game code to its chat.
+type chatRooms struct {
+	mu    sync.Mutex
+	rooms map[string]*chatRoom
+}
+
+// room returns the chat for a game code, creating it with the given host if needed. Assumes mu is held.
+func (c *chatRooms) room(gameCode, hostID string) *chatRoom {
+	room, ok := c.rooms[gameCode]
+	if !ok {
+		room = &chatRoom{
+			hostID: hostID,
+			muted:  make(map[string]bool),
+			sent:   make(map[string][]time.Time),
+		}
+		c.rooms[gameCode] = room
+	}
+	return room
+}
+
+// allow records a send and reports whether the client is within the rate limit.
+func (r *chatRoom) allow(clientID string, now time.Time) bool {
+	recent := r.sent[clientID][:0]
+	for _, t := range r.sent[clientID] {
+		if now.Sub(t) < ChatRateWindow {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= ChatRateLimit {
+		r.sent[clientID] = recent
+		return false
+	}
+	r.sent[clientID] = append(recent, now)
+	return true
+}
+
+// record appends a message to the history, dropping the oldest beyond ChatHistorySize.
+func (r *chatRoom) record(message protocol.ChatMessagePayload) {
+	r.history = append(r.history, message)
+	if len(r.history) > ChatHistorySize {
+		r.history = r.history[len(r.history)-ChatHistorySize:]
+	}
+}
+
+// chatMember is someone in a lobby or game as far as chat is concerned.
+type chatMember struct {
+	client *Client
+	team   int // Team number in a running game, 0 in a lobby
+}
+
+// canSee decides whether a member may read a message. Spectators only see their
+// own channel and players never see it, so nobody watching can coach a player.
+func (m chatMember) canSee(message protocol.ChatMessagePayload) bool {
+	switch message.Channel {
+	case protocol.ChatChannelSpectators:
+		return m.client.Spectator
+	case protocol.ChatChannelTeam:
+		return !m.client.Spectator && m.team == message.TeamNumber
+	default:
+		return !m.client.Spectator
+	}
+}
+
+// chatMembers lists the humans in the lobby or game with the given code.
+func (h *Hub) chatMembers(gameCode string) []chatMember {
+	h.gameMu.RLock()
+	gameInstance, inGame := h.games[gameCode]
+	h.gameMu.RUnlock()
+
+	var members []chatMember
+	h.clientMu.RLock()
+	for c, code := range h.clientToGame {
+		if code == gameCode && !c.Bot {
+			members = append(members, chatMember{client: c})
+		}
+	}
+	h.clientMu.RUnlock()
+
+	// Ask the game for teams without holding clientMu, the game may be sending messages
+	for i, m := range members {
+		if inGame && !m.client.Spectator {
+			members[i].team = gameInstance.TeamNumber(m.client.ID)
+		}
+	}
+	return members
+}
+
+// memberOf looks up a single client in the lobby or game with the given code.
+func (h *Hub) memberOf(client *Client, gameCode string) chatMember {
+	for _, m := range h.chatMembers(gameCode) {
+		if m.client == client {
+			return m
+		}
+	}
+	return chatMember{client: client}
+}
+
+// handleChat validates a chat message and delivers it to everyone allowed to read it.
+func (h *Hub) handleChat(client *Client, msg protocol.Message) {
+	h.clientMu.RLock()
+	gameCode, inRoom := h.clientToGame[client]
+	h.clientMu.RUnlock()
+	if !inRoom {
+		h.sendErrorToClient(client, "You are not in a lobby or game.")
+		return
+	}
+
+	var payload protocol.ChatPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling chat payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid chat message format.")
+		return
+	}
+	text := strings.TrimSpace(payload.Text)
+	if text == "" {
+		return
+	}
+	if utf8.RuneCountInString(text) > MaxChatLength {
+		h.sendErrorToClient(client, "Chat message is too long.")
+		return
+	}
+
+	sender := h.memberOf(client, gameCode)
+	channel := payload.Channel
+	if channel == "" {
+		channel = protocol.ChatChannelAll
+	}
+	switch {
+	case client.Spectator:
+		channel = protocol.ChatChannelSpectators // Spectators only talk among themselves
+	case channel == protocol.ChatChannelSpectators:
+		h.sendErrorToClient(client, "Only spectators can use that channel.")
+		return
+	case channel == protocol.ChatChannelTeam && sender.team == 0:
+		h.sendErrorToClient(client, "Team chat is only available once the game has started.")
+		return
+	case channel != protocol.ChatChannelAll && channel != protocol.ChatChannelTeam:
+		h.sendErrorToClient(client, "Unknown chat channel.")
+		return
+	}
+
+	message := protocol.ChatMessagePayload{
+		Channel:    channel,
+		SenderID:   client.ID,
+		SenderName: client.Name,
+		Text:       text,
+		SentAt:     time.Now().Format(time.RFC3339),
+	}
+	if channel == protocol.ChatChannelTeam {
+		message.TeamNumber = sender.team
+	}
+
+	h.chat.mu.Lock()
+	room := h.chat.room(gameCode, "")
+	if room.muted[client.ID] {
+		h.chat.mu.Unlock()
+		h.sendErrorToClient(client, "You have been muted by the host.")
+		return
+	}
+	if !room.allow(client.ID, time.Now()) {
+		h.chat.mu.Unlock()
+		h.sendErrorToClient(client, "You are sending messages too quickly.")
+		return
+	}
+	room.record(message)
+	h.chat.mu.Unlock()
+
+	msgBytes, _ := protocol.NewMessage("chat", message)
+	for _, m := range h.chatMembers(gameCode) {
+		if m.canSee(message) {
+			h.sendMessageToClient(m.client.ID, msgBytes)
+		}
+	}
+}
+
+// sendChatHistory sends a client the recent messages it is allowed to read.
+func (h *Hub) sendChatHistory(client *Client, gameCode string) {
+	member := h.memberOf(client, gameCode)
+
+	h.chat.mu.Lock()
+	messages := []protocol.ChatMessagePayload{}
+	if room, ok := h.chat.rooms[gameCode]; ok {
+		for _, message := range room.history {
+			if member.canSee(message) {
+				messages = append(messages, message)
+			}
+		}
+	}
+	h.chat.mu.Unlock()
+
+	msgBytes, _ := protocol.NewMessage("chat_history", protocol.ChatHistoryPayload{Messages: messages})
+	h.sendMessageToClient(client.ID, msgBytes)
+}
+
+// handleMutePlayer lets the host mute or unmute someone in their lobby or game.
+func (h *Hub) handleMutePlayer(client *Client, msg protocol.Message) {
+	h.clientMu.RLock()
+	gameCode, inRoom := h.clientToGame[client]
+	h.clientMu.RUnlock()
+	if !inRoom {
+		h.sendErrorToClient(client, "You are not in a lobby or game.")
+		return
+	}
+
+	var payload protocol.MutePlayerPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling mute_player payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid mute_player message format.")
+		return
+	}
+
+	// The lobby's current host decides until the game starts, then whoever hosted it
+	hostID := ""
+	h.lobbyMu.RLock()
+	if lobby, ok := h.lobbies[gameCode]; ok && lobby.Host() != nil {
+		hostID = lobby.Host().ID
+	}
+	h.lobbyMu.RUnlock()
+
+	h.chat.mu.Lock()
+	room := h.chat.room(gameCode, hostID)
+	if hostID == "" {
+		hostID = room.hostID
+	}
+	if client.ID != hostID {
+		h.chat.mu.Unlock()
+		h.sendErrorToClient(client, "Only the host can mute players.")
+		return
+	}
+	if payload.PlayerID == client.ID {
+		h.chat.mu.Unlock()
+		h.sendErrorToClient(client, "You can't mute yourself.")
+		return
+	}
+	room.muted[payload.PlayerID] = payload.Muted
+	h.chat.mu.Unlock()
+
+	log.Printf("Host %s set muted=%t for %s in %s.", client.ID, payload.Muted, payload.PlayerID, gameCode)
+	msgBytes, _ := protocol.NewMessage("player_muted", payload)
+	for _, m := range h.chatMembers(gameCode) {
+		h.sendMessageToClient(m.client.ID, msgBytes)
+	}
+}
+
+// setChatHost records who may mute players once a lobby turns into a game.
+func (h *Hub) setChatHost(gameCode, hostID string) {
+	h.chat.mu.Lock()
+	defer h.chat.mu.Unlock()
+	h.chat.room(gameCode, hostID).hostID = hostID
+}
+
+// closeChat forgets the chat of a lobby that was abandoned.
+func (h *Hub) closeChat(gameCode string) {
+	h.chat.mu.Lock()
+	defer h.chat.mu.Unlock()
+	delete(h.chat.rooms, gameCode)
+}
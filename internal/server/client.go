@@ -18,7 +18,10 @@ type Client struct {
 	ID   			string // Unique identifier for the client/player
 	Name 			string // Player's chosen name
 	DesiredTeam 	shared.TeamEnum // Desired team for the player
-	PointsGoal 		int // Points goal for the game
+	SessionToken 	string // Token that lets the player reclaim their seat after a disconnect
+	Bot 			bool // Seat filled by a server-side bot; has no connection
+	Spectator 		bool // Watching a running game without a seat
+	AccountID 		string // Registered account the connection logged in with, empty for a guest
 }
 
 // ReadPump handles incoming messages from the WebSocket connection.
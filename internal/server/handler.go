@@ -16,8 +16,16 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-// ServeWs handles WebSocket requests from clients.
+// ServeWs handles WebSocket requests from clients. A login token, passed as the
+// token query parameter or a bearer token, ties the connection to an account;
+// without one the client plays as a guest.
 func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	account, ok := hub.authenticate(r)
+	if !ok {
+		http.Error(w, "Invalid or expired login token", http.StatusUnauthorized)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade connection: %v", err)
@@ -29,6 +37,8 @@ func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
 		conn: conn,
 		send: make(chan []byte, 256),
 		// Name, ID, DesiredTeam will be set later in the process
+		AccountID: account.ID,
+		Name:      account.Username,
 	}
 	hub.register <- client
 
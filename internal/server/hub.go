@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"math/rand"
 	"strings"
@@ -23,10 +24,37 @@ type clientMessage struct {
 
 const gameCodeLength = 5 // Length of the unique game code
 
+// DefaultReconnectGracePeriod is how long a disconnected player's seat is held by default.
+const DefaultReconnectGracePeriod = 60 * time.Second
+
+// Default pacing of every game: how long a finished trick stays on the table and
+// how long the next round waits for everyone to be ready.
+const (
+	DefaultTrickPause   = 2 * time.Second
+	DefaultReadyTimeout = 30 * time.Second
+)
+
+// Config holds the tunable Hub settings.
+type Config struct {
+	ReconnectGracePeriod time.Duration // How long a seat is held after a disconnect (0 forfeits immediately)
+	TrickPause           time.Duration // How long a finished trick stays on the table (0 for no pause)
+	ReadyTimeout         time.Duration // How long the next round waits for everyone to be ready (0 starts it at once)
+}
+
+// session binds a reconnect token to a seat in a game.
+type session struct {
+	token     string
+	playerID  string
+	name      string
+	accountID string // Account the seat was taken with, empty for a guest
+	gameCode  string
+	pending   *time.Timer // Forfeit timer while the player is disconnected, nil otherwise
+}
+
 // Hub manages active WebSocket connections, lobbies, and game rooms.
 type Hub struct {
 	clients        map[*Client]bool
-	lobbies        map[string][]*Client  // Map game code to list of clients in the lobby
+	lobbies        map[string]*Lobby     // Map game code to the lobby gathering its players
 	games          map[string]*game.Game // Map game code to game instance
 	clientToGame   map[*Client]string    // Map client to game code (lobby or active game)
 	processMessage chan clientMessage
@@ -38,17 +66,28 @@ type Hub struct {
 	gameMu         sync.RWMutex
 	dbMu           sync.RWMutex
 	rng            *rand.Rand
+	sessions       map[string]*session // Map session token to the seat it reclaims
+	sessionMu      sync.Mutex
+	config         Config
+	chat           chatRooms         // Chat of every lobby and game, by game code
+	matches        map[string]*Match // Map game code to the series its table is playing
+	matchMu        sync.Mutex
+	queue          []*Client // Players waiting for a quick match, in the order they asked
+	queueMu        sync.Mutex
+	tournaments    map[string]*hostedTournament // Running tournaments and those run since the server started, by ID
+	tournamentOf   map[string]string            // Map game code of a tournament table to its tournament ID
+	tournamentMu   sync.Mutex
 }
 
 // NewHub creates a new Hub instance.
-func NewHub(db *database.Service) *Hub {
+func NewHub(db *database.Service, config Config) *Hub {
 	// Seed the random number generator
 	source := rand.NewSource(time.Now().UnixNano())
 	rng := rand.New(source)
 
 	return &Hub{
 		clients:        make(map[*Client]bool),
-		lobbies:        make(map[string][]*Client),
+		lobbies:        make(map[string]*Lobby),
 		games:          make(map[string]*game.Game),
 		clientToGame:   make(map[*Client]string),
 		processMessage: make(chan clientMessage),
@@ -56,6 +95,12 @@ func NewHub(db *database.Service) *Hub {
 		unregister:     make(chan *Client),
 		rng:            rng,
 		db:             db,
+		sessions:       make(map[string]*session),
+		config:         config,
+		chat:           chatRooms{rooms: make(map[string]*chatRoom)},
+		matches:        make(map[string]*Match),
+		tournaments:    make(map[string]*hostedTournament),
+		tournamentOf:   make(map[string]string),
 	}
 }
 
@@ -97,6 +142,7 @@ func (h *Hub) Run() {
 			h.clientMu.Unlock()
 
 		case client := <-h.unregister:
+			h.leaveQueue(client)
 			h.clientMu.Lock()
 			gameCode, inGameOrLobby := h.clientToGame[client]
 			_, clientExists := h.clients[client]
@@ -115,23 +161,21 @@ func (h *Hub) Run() {
 				lobby, lobbyExists := h.lobbies[gameCode]
 				if lobbyExists {
 					// Remove client from lobby
-					newLobby := []*Client{}
-					for _, c := range lobby {
-						if c != client {
-							newLobby = append(newLobby, c)
-						}
-					}
-					if len(newLobby) > 0 {
-						h.lobbies[gameCode] = newLobby
+					lobby.remove(client)
+					h.revokeSession(client.SessionToken)
+					if lobby.Host() != nil || lobby.Reserved != nil {
+						// Tournament tables wait for their players to come back
+						h.lobbyMu.Unlock() // Unlock lobbyMu before broadcasting
 						log.Printf("Client %s removed from lobby %s.", client.ID, gameCode)
 						// Broadcast updated lobby state
-						h.broadcastLobbyUpdate(gameCode, newLobby)
+						h.broadcastLobbyUpdate(gameCode)
 					} else {
-						// Last player left, delete lobby
+						// Last human left, delete lobby (and any bots in it)
 						delete(h.lobbies, gameCode)
+						h.lobbyMu.Unlock() // Unlock lobbyMu after lobby modification
+						h.closeChat(gameCode)
 						log.Printf("Client %s left lobby %s. Lobby deleted.", client.ID, gameCode)
 					}
-					h.lobbyMu.Unlock() // Unlock lobbyMu after lobby modification
 				} else {
 					h.lobbyMu.Unlock() // Unlock lobbyMu if not found in lobbies
 
@@ -140,11 +184,16 @@ func (h *Hub) Run() {
 					gameInstance, gameExists := h.games[gameCode]
 					h.gameMu.RUnlock()
 
-					if gameExists {
+					if gameExists && client.Spectator {
+						log.Printf("Spectator %s left game %s.", client.ID, gameCode)
+						gameInstance.RemoveSpectator(client.ID)
+					} else if gameExists && h.inRematchVote(gameCode) {
+						log.Printf("Client %s left table %s during the rematch vote.", client.ID, gameCode)
+						h.revokeSession(client.SessionToken)
+						h.leaveTable(gameCode, client.ID)
+					} else if gameExists {
 						log.Printf("Client %s was in game %s. Notifying game.", client.ID, gameCode)
-						// Notify the game instance about the disconnect
-						go gameInstance.HandlePlayerDisconnect(client.ID) // Run in goroutine to avoid blocking hub
-						// TODO: Game cleanup logic might be needed here or triggered by the game instance setting its state.
+						h.holdSeat(client, gameInstance)
 					} else {
 						log.Printf("Client %s disconnected but was mapped to non-existent game/lobby code %s", client.ID, gameCode)
 					}
@@ -169,7 +218,37 @@ func (h *Hub) handleMessage(client *Client, msg protocol.Message) {
 		h.handleCreateGame(client, msg)
 	case "join_game":
 		h.handleJoinGame(client, msg)
-	case "play_card", "declare":
+	case "rejoin_game":
+		h.handleRejoinGame(client, msg)
+	case "spectate_game":
+		h.handleSpectateGame(client, msg)
+	case "chat":
+		h.handleChat(client, msg)
+	case "mute_player":
+		h.handleMutePlayer(client, msg)
+	case "add_bot":
+		h.handleAddBot(client, msg)
+	case "remove_bot":
+		h.handleRemoveBot(client, msg)
+	case "choose_seat":
+		h.handleChooseSeat(client, msg)
+	case "swap_request":
+		h.handleSwapRequest(client, msg)
+	case "lock_seats":
+		h.handleLockSeats(client, msg)
+	case "randomize_seats":
+		h.handleRandomizeSeats(client)
+	case "start_game":
+		h.handleStartGame(client)
+	case "list_lobbies":
+		h.handleListLobbies(client)
+	case "quick_match":
+		h.handleQuickMatch(client, msg)
+	case "leave_queue":
+		h.handleLeaveQueue(client)
+	case "rematch":
+		h.handleRematch(client, msg)
+	case "play_card", "declare", "declarations_done", "call_card", "ready":
 		h.handleGameAction(client, msg)
 	case "ping":
 		pongMsg, _ := protocol.NewMessage("pong", nil)
@@ -197,11 +276,13 @@ func (h *Hub) handleCreateGame(client *Client, msg protocol.Message) {
 		h.sendErrorToClient(client, "Invalid create_game message format.")
 		return
 	}
-	if payload.Name == "" {
-		log.Printf("Client %s tried to create game with an empty name.", client.ID)
-		h.sendErrorToClient(client, "Name cannot be empty.")
+	name, err := h.playerName(client, payload.Name)
+	if err != nil {
+		log.Printf("Client %s tried to create game with name '%s': %v", client.ID, payload.Name, err)
+		h.sendErrorToClient(client, err.Error())
 		return
 	}
+	payload.Name = name
 	if payload.DesiredTeam != 1 && payload.DesiredTeam != 2 {
 		log.Printf("Client %s tried to create game with an invalid desired team: %d", client.ID, payload.DesiredTeam)
 		h.sendErrorToClient(client, "Invalid desired team.")
@@ -212,20 +293,125 @@ func (h *Hub) handleCreateGame(client *Client, msg protocol.Message) {
 		h.sendErrorToClient(client, "Invalid points goal.")
 		return
 	}
+	if payload.PlayerCount == 0 {
+		payload.PlayerCount = game.FourPlayers
+	}
+	if payload.PlayerCount < game.TwoPlayers || payload.PlayerCount > game.FourPlayers {
+		log.Printf("Client %s tried to create game with an invalid player count: %d", client.ID, payload.PlayerCount)
+		h.sendErrorToClient(client, "Invalid player count.")
+		return
+	}
+	mortoRule := game.MortoRule(payload.MortoRule)
+	if mortoRule == "" {
+		mortoRule = game.MortoAside
+	}
+	if mortoRule != game.MortoAside && mortoRule != game.MortoLastTrick {
+		log.Printf("Client %s tried to create game with an invalid morto rule: %s", client.ID, payload.MortoRule)
+		h.sendErrorToClient(client, "Invalid morto rule.")
+		return
+	}
+	if payload.Ruleset == "" {
+		payload.Ruleset = game.DefaultRuleset
+	}
+	rules, ok := game.LookupRuleset(payload.Ruleset)
+	if !ok {
+		log.Printf("Client %s tried to create game with an unknown ruleset: %s", client.ID, payload.Ruleset)
+		h.sendErrorToClient(client, "Unknown ruleset.")
+		return
+	}
+	if !rules.SupportsPlayerCount(payload.PlayerCount) {
+		log.Printf("Client %s tried to create a %d-player game with ruleset %s", client.ID, payload.PlayerCount, rules.Name())
+		h.sendErrorToClient(client, fmt.Sprintf("The %s rules can't be played with %d players.", rules.Name(), payload.PlayerCount))
+		return
+	}
+	rounding := game.RoundingPolicy(payload.Rounding)
+	if rounding == "" {
+		rounding = game.RoundTruncate
+	}
+	if rounding != game.RoundTruncate && rounding != game.RoundCarry && rounding != game.RoundLastTrick {
+		log.Printf("Client %s tried to create game with an invalid rounding policy: %s", client.ID, payload.Rounding)
+		h.sendErrorToClient(client, "Invalid rounding policy.")
+		return
+	}
+	if payload.CappottoBonus < 0 || payload.CappottoBonus > game.MaxCappottoBonus {
+		log.Printf("Client %s tried to create game with an invalid cappotto bonus: %d", client.ID, payload.CappottoBonus)
+		h.sendErrorToClient(client, "Invalid cappotto bonus.")
+		return
+	}
+	if _, briscola := rules.(*game.BriscolaRules); payload.CappottoBonus > 0 && (briscola || rules.Mode() != game.ModeClassic) {
+		log.Printf("Client %s asked for a cappotto bonus with ruleset %s", client.ID, rules.Name())
+		h.sendErrorToClient(client, fmt.Sprintf("The %s rules have no cappotto bonus.", rules.Name()))
+		return
+	}
+	if payload.ExcludeDeclarations && rules.Mode() == game.ModeChiamare {
+		log.Printf("Client %s tried to keep declarations out of the points goal with ruleset %s", client.ID, rules.Name())
+		h.sendErrorToClient(client, "Declarations always count toward the points goal when playing a chiamare.")
+		return
+	}
+	turnTimeout := time.Duration(payload.TurnTimeout) * time.Second
+	timeBank := time.Duration(payload.TimeBank) * time.Second
+	if turnTimeout < 0 || turnTimeout > game.MaxTurnTimeout || timeBank < 0 || timeBank > game.MaxTimeBank {
+		log.Printf("Client %s tried to create game with an invalid clock: %ds per move, %ds bank", client.ID, payload.TurnTimeout, payload.TimeBank)
+		h.sendErrorToClient(client, "Invalid turn timer.")
+		return
+	}
+	if payload.Ranked && client.AccountID == "" {
+		log.Printf("Client %s tried to create a ranked game without logging in.", client.ID)
+		h.sendErrorToClient(client, "Log in to create a ranked game.")
+		return
+	}
+	if payload.Ranked && game.TeamCount(payload.PlayerCount, rules.Mode()) != 2 {
+		log.Printf("Client %s tried to create a ranked %d-player game with ruleset %s", client.ID, payload.PlayerCount, rules.Name())
+		h.sendErrorToClient(client, "Ranked games are played by two sides.")
+		return
+	}
+	if payload.BestOf == 0 {
+		payload.BestOf = 1
+	}
+	if !validBestOf(payload.BestOf) {
+		log.Printf("Client %s tried to create game with an invalid match length: %d", client.ID, payload.BestOf)
+		h.sendErrorToClient(client, "Invalid match length.")
+		return
+	}
+	spectatorHandDelay := time.Duration(payload.SpectatorHandDelay) * time.Second
+	if spectatorHandDelay < 0 || spectatorHandDelay > game.MaxSpectatorHandDelay {
+		log.Printf("Client %s tried to create game with an invalid spectator hand delay: %d", client.ID, payload.SpectatorHandDelay)
+		h.sendErrorToClient(client, "Invalid spectator hand delay.")
+		return
+	}
 
 	// Generate unique game code
 	gameCode := h.generateGameCode()
+	h.leaveQueue(client) // A player who sets up their own table stops waiting for a quick match
 
 	// Update client state and create lobby
 	h.clientMu.Lock()
 	client.Name = payload.Name
 	client.DesiredTeam = payload.DesiredTeam // Set desired team
-	client.PointsGoal = payload.PointsGoal   // Set points goal
 	h.clientToGame[client] = gameCode
 	h.clientMu.Unlock()
 
+	lobby := newLobby(payload.PlayerCount, payload.BestOf, game.Settings{
+		TargetScore: payload.PointsGoal,
+		MortoRule:   mortoRule,
+		Rules:       rules,
+
+		Rounding:            rounding,
+		CappottoBonus:       payload.CappottoBonus,
+		ExcludeDeclarations: payload.ExcludeDeclarations,
+		Ranked:              payload.Ranked,
+
+		SpectatorHandDelay: spectatorHandDelay,
+		Silent:             payload.Silent,
+		TurnTimeout:        turnTimeout,
+		TimeBank:           timeBank,
+		TrickPause:         h.config.TrickPause,
+		ReadyTimeout:       h.config.ReadyTimeout,
+	})
+	lobby.Public = payload.Public
+	lobby.add(client, "")
 	h.lobbyMu.Lock()
-	h.lobbies[gameCode] = []*Client{client}
+	h.lobbies[gameCode] = lobby
 	h.lobbyMu.Unlock()
 
 	log.Printf("Client %s (%s) created lobby %s", client.ID, client.Name, gameCode)
@@ -234,8 +420,9 @@ func (h *Hub) handleCreateGame(client *Client, msg protocol.Message) {
 	createdPayload := protocol.GameCreatedPayload{GameCode: gameCode}
 	createdMsg, _ := protocol.NewMessage("game_created", createdPayload)
 	h.sendMessageToClient(client.ID, createdMsg)
+	h.issueSession(client, gameCode)
 
-	h.broadcastLobbyUpdate(gameCode, []*Client{client}) // Send initial lobby state
+	h.broadcastLobbyUpdate(gameCode) // Send initial lobby state
 }
 
 // handleJoinGame handles a request to join an existing game lobby.
@@ -255,11 +442,13 @@ func (h *Hub) handleJoinGame(client *Client, msg protocol.Message) {
 		h.sendJoinError(client, "Invalid join_game message format.")
 		return
 	}
-	if payload.Name == "" {
-		log.Printf("Client %s tried to join with an empty name.", client.ID)
-		h.sendJoinError(client, "Name cannot be empty.")
+	name, err := h.playerName(client, payload.Name)
+	if err != nil {
+		log.Printf("Client %s tried to join with name '%s': %v", client.ID, payload.Name, err)
+		h.sendJoinError(client, err.Error())
 		return
 	}
+	payload.Name = name
 	if payload.GameCode == "" {
 		log.Printf("Client %s tried to join without a game code.", client.ID)
 		h.sendJoinError(client, "Game code cannot be empty.")
@@ -281,15 +470,21 @@ func (h *Hub) handleJoinGame(client *Client, msg protocol.Message) {
 		return
 	}
 
-	if len(lobby) >= 4 {
+	if lobby.IsFull() {
 		h.lobbyMu.Unlock()
 		log.Printf("Client %s tried to join full lobby %s", client.ID, gameCode)
 		h.sendJoinError(client, "Game lobby is full.")
 		return
 	}
+	if lobby.Settings.Ranked && client.AccountID == "" {
+		h.lobbyMu.Unlock()
+		log.Printf("Guest %s tried to join ranked lobby %s", client.ID, gameCode)
+		h.sendJoinError(client, "Log in to join a ranked game.")
+		return
+	}
 
 	// Check for duplicate name within this lobby
-	for _, existingClient := range lobby {
+	for _, existingClient := range lobby.Clients {
 		if existingClient.Name == payload.Name {
 			h.lobbyMu.Unlock()
 			log.Printf("Client %s tried to join lobby %s with duplicate name '%s'", client.ID, gameCode, payload.Name)
@@ -298,12 +493,18 @@ func (h *Hub) handleJoinGame(client *Client, msg protocol.Message) {
 		}
 	}
 
-	// Add client to lobby
+	// Add client to lobby, on their desired team if it has a free seat
 	client.Name = payload.Name               // Set name before adding to lobby list
 	client.DesiredTeam = payload.DesiredTeam // Set desired team
-	client.PointsGoal = -1
-	newLobby := append(lobby, client)
-	h.lobbies[gameCode] = newLobby
+	if !lobby.add(client, payload.SeatToken) {
+		h.lobbyMu.Unlock()
+		log.Printf("Client %s (%s) has no seat at tournament table %s", client.ID, payload.Name, gameCode)
+		h.sendJoinError(client, "Your join token holds no free seat at this tournament table.")
+		return
+	}
+	lobbySize := len(lobby.Clients)
+	// Tournament tables start once all four players are in
+	autoStart := lobby.Reserved != nil
 	h.lobbyMu.Unlock() // Unlock lobbyMu after modification
 
 	// Update client mapping
@@ -311,60 +512,348 @@ func (h *Hub) handleJoinGame(client *Client, msg protocol.Message) {
 	h.clientToGame[client] = gameCode
 	h.clientMu.Unlock()
 
-	log.Printf("Client %s (%s) joined lobby %s. Lobby size: %d", client.ID, client.Name, gameCode, len(newLobby))
+	log.Printf("Client %s (%s) joined lobby %s. Lobby size: %d", client.ID, client.Name, gameCode, lobbySize)
+	h.leaveQueue(client)
+	h.issueSession(client, gameCode)
+	h.sendChatHistory(client, gameCode)
 
 	// Broadcast updated lobby state
-	h.broadcastLobbyUpdate(gameCode, newLobby)
-
-	// Check if lobby is full and start game
-	if len(newLobby) == 4 {
-		log.Printf("Lobby %s is full. Starting game...", gameCode)
-
-		// Lock gameMu before modifying games map
-		h.gameMu.Lock()
-		// Lock lobbyMu to safely delete the lobby
-		h.lobbyMu.Lock()
-
-		// Double-check lobby exists and has 4 players before proceeding
-		finalLobby, finalLobbyExists := h.lobbies[gameCode]
-		if !finalLobbyExists || len(finalLobby) != 4 {
-			// Should not happen if locks are correct, but good safeguard
-			log.Printf("Error: Lobby %s state changed unexpectedly before game start. Aborting start.", gameCode)
-			h.lobbyMu.Unlock()
-			h.gameMu.Unlock()
-			errorMsgBytes, _ := protocol.NewMessage("error", protocol.ErrorPayload{Message: "Failed to start game due to internal error."})
-			h.broadcastToLobby(gameCode, errorMsgBytes)
-			return
+	h.broadcastLobbyUpdate(gameCode)
+	if autoStart {
+		h.startGameIfFull(gameCode)
+	}
+}
+
+// handleStartGame lets the lobby host start the game once every seat is taken.
+func (h *Hub) handleStartGame(client *Client) {
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.RLock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	isHost := lobbyExists && lobby.Host() == client
+	full := lobbyExists && lobby.IsFull()
+	h.lobbyMu.RUnlock()
+	if !inLobby || !lobbyExists {
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if !isHost {
+		log.Printf("Client %s tried to start lobby %s but is not the host.", client.ID, gameCode)
+		h.sendErrorToClient(client, "Only the host can start the game.")
+		return
+	}
+	if !full {
+		h.sendErrorToClient(client, "Every seat must be taken before the game starts.")
+		return
+	}
+	h.startGameIfFull(gameCode)
+}
+
+// startGameIfFull creates and starts the game once every seat in the lobby is
+// taken. Players sit exactly where the lobby shows them.
+func (h *Hub) startGameIfFull(gameCode string) {
+	h.lobbyMu.RLock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	full := lobbyExists && lobby.IsFull()
+	h.lobbyMu.RUnlock()
+	if !full {
+		return
+	}
+
+	log.Printf("Lobby %s is full. Starting game...", gameCode)
+
+	// Lock gameMu before modifying games map
+	h.gameMu.Lock()
+	// Lock lobbyMu to safely delete the lobby
+	h.lobbyMu.Lock()
+
+	// Double-check lobby exists and is full before proceeding
+	lobby, finalLobbyExists := h.lobbies[gameCode]
+	if !finalLobbyExists || !lobby.IsFull() {
+		// Should not happen if locks are correct, but good safeguard
+		log.Printf("Error: Lobby %s state changed unexpectedly before game start. Aborting start.", gameCode)
+		h.lobbyMu.Unlock()
+		h.gameMu.Unlock()
+		errorMsgBytes, _ := protocol.NewMessage("error", protocol.ErrorPayload{Message: "Failed to start game due to internal error."})
+		h.broadcastToLobby(gameCode, errorMsgBytes)
+		return
+	}
+
+	// Create and start the game
+	finalLobby := lobby.Seats
+	if host := lobby.Host(); host != nil {
+		h.setChatHost(gameCode, host.ID)
+	}
+	gamePlayers := convertClientsToGamePlayers(finalLobby) // Use finalLobby slice
+	bots := make(map[string]bool)
+	for _, c := range finalLobby {
+		bots[c.ID] = c.Bot
+	}
+	newGame := h.newTableGame(gameCode, gamePlayers, bots, lobby.Settings)
+	h.games[gameCode] = newGame // Add to games map using gameCode
+	h.matchMu.Lock()
+	h.matches[gameCode] = newMatch(lobby.BestOf, lobby.Settings)
+	h.matchMu.Unlock()
+
+	// Remove the lobby now that the game is created
+	delete(h.lobbies, gameCode)
+
+	h.lobbyMu.Unlock() // Unlock lobbyMu
+	h.gameMu.Unlock()  // Unlock gameMu
+
+	log.Printf("Game instance created for code %s with ID %s. Players: %v", gameCode, newGame.ID, playerNames(finalLobby))
+
+	// Start the game loop/first round in a goroutine
+	// This function should handle sending game_start, deal_hand, etc.
+	go newGame.StartGameLoop(h.sendMessageToClient) // Pass the callback
+}
+
+// handleAddBot lets the lobby host fill a seat on a team with a bot.
+func (h *Hub) handleAddBot(client *Client, msg protocol.Message) {
+	var payload protocol.AddBotPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling add_bot payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid add_bot message format.")
+		return
+	}
+	if payload.Team != shared.TeamRed && payload.Team != shared.TeamBlue {
+		log.Printf("Client %s tried to add a bot to an invalid team: %d", client.ID, payload.Team)
+		h.sendErrorToClient(client, "Invalid team.")
+		return
+	}
+
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.Lock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	if !inLobby || !lobbyExists {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if lobby.Host() != client {
+		h.lobbyMu.Unlock()
+		log.Printf("Client %s tried to add a bot to lobby %s but is not the host.", client.ID, gameCode)
+		h.sendErrorToClient(client, "Only the host can add bots.")
+		return
+	}
+	if lobby.IsFull() {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Game lobby is full.")
+		return
+	}
+	if lobby.Settings.Ranked {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Bots can't play ranked games.")
+		return
+	}
+	if lobby.Reserved != nil {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Bots can't play tournament games.")
+		return
+	}
+	seat := lobby.freeSeat(payload.Team, lobby.PlayerCount == game.FourPlayers && lobby.Settings.Rules.Mode() == game.ModeClassic)
+	if seat < 0 {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "That team is already full.")
+		return
+	}
+	names := make(map[string]bool)
+	for _, c := range lobby.Clients {
+		names[c.Name] = true
+	}
+
+	botNumber := 1
+	for names[fmt.Sprintf("Bot %d", botNumber)] {
+		botNumber++
+	}
+	bot := &Client{
+		ID:          uuid.NewString(),
+		Name:        fmt.Sprintf("Bot %d", botNumber),
+		DesiredTeam: payload.Team,
+		Bot:         true,
+	}
+	lobby.Clients = append(lobby.Clients, bot)
+	lobby.sit(bot, seat)
+	h.lobbyMu.Unlock()
+
+	log.Printf("Client %s added %s to seat %d in lobby %s.", client.ID, bot.Name, seat, gameCode)
+	h.broadcastLobbyUpdate(gameCode)
+}
+
+// handleRemoveBot lets the lobby host free a seat taken by a bot.
+func (h *Hub) handleRemoveBot(client *Client, msg protocol.Message) {
+	var payload protocol.RemoveBotPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling remove_bot payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid remove_bot message format.")
+		return
+	}
+
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.Lock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	if !inLobby || !lobbyExists {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if lobby.Host() != client {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Only the host can remove bots.")
+		return
+	}
+	var bot *Client
+	for _, c := range lobby.Clients {
+		if c.Bot && c.ID == payload.BotID {
+			bot = c
 		}
+	}
+	if bot == nil {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Bot not found.")
+		return
+	}
+	lobby.remove(bot)
+	h.lobbyMu.Unlock()
 
-		// Create and start the game
-		var targetScore int
-		for _, c := range finalLobby {
-			if c != nil && c.PointsGoal > 0 {
-				targetScore = c.PointsGoal // Use the first valid points goal found
-				break
-			}
+	log.Printf("Client %s removed bot %s from lobby %s.", client.ID, payload.BotID, gameCode)
+	h.broadcastLobbyUpdate(gameCode)
+}
+
+// handleRejoinGame rebinds a reconnecting client to the seat its session token holds.
+func (h *Hub) handleRejoinGame(client *Client, msg protocol.Message) {
+	h.clientMu.RLock()
+	_, alreadyInGame := h.clientToGame[client]
+	h.clientMu.RUnlock()
+	if alreadyInGame {
+		log.Printf("Client %s tried to rejoin but is already associated with a game.", client.ID)
+		h.sendJoinError(client, "Already in a game or lobby.")
+		return
+	}
+
+	var payload protocol.RejoinGamePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling rejoin_game payload from client %s: %v", client.ID, err)
+		h.sendJoinError(client, "Invalid rejoin_game message format.")
+		return
+	}
+
+	h.sessionMu.Lock()
+	s, ok := h.sessions[payload.Token]
+	if ok && s.pending != nil {
+		s.pending.Stop()
+		s.pending = nil
+	}
+	h.sessionMu.Unlock()
+	if !ok {
+		log.Printf("Client %s tried to rejoin with an unknown or expired session.", client.ID)
+		h.sendJoinError(client, "Session expired or not found.")
+		return
+	}
+
+	h.gameMu.RLock()
+	gameInstance, gameExists := h.games[s.gameCode]
+	h.gameMu.RUnlock()
+	if !gameExists {
+		log.Printf("Client %s tried to rejoin game %s, which has not started or no longer exists.", client.ID, s.gameCode)
+		h.sendJoinError(client, "Game not found or not active.")
+		return
+	}
+
+	// Drop a stale connection still bound to this seat (e.g. a half-open socket)
+	h.clientMu.Lock()
+	for c := range h.clients {
+		if c != client && c.ID == s.playerID {
+			delete(h.clients, c)
+			delete(h.clientToGame, c)
+			close(c.send)
+			log.Printf("Dropped stale connection for player %s.", s.playerID)
 		}
-		gamePlayers := convertClientsToGamePlayers(finalLobby) // Use finalLobby slice
-		newGame := game.NewGame(gamePlayers, targetScore, h.db)
-		h.games[gameCode] = newGame // Add to games map using gameCode
+	}
+	oldID := client.ID
+	client.ID = s.playerID
+	client.Name = s.name
+	client.AccountID = s.accountID
+	client.SessionToken = s.token
+	h.clientToGame[client] = s.gameCode
+	h.clientMu.Unlock()
 
-		// Remove the lobby now that the game is created
-		delete(h.lobbies, gameCode)
+	log.Printf("Client %s rejoined game %s as player %s (%s).", oldID, s.gameCode, client.ID, client.Name)
+	if !gameInstance.ReconnectPlayer(client.ID) {
+		h.clientMu.Lock()
+		delete(h.clientToGame, client)
+		h.clientMu.Unlock()
+		h.revokeSession(s.token)
+		h.sendJoinError(client, "Game is already over.")
+		return
+	}
+	h.sendChatHistory(client, s.gameCode)
+}
 
-		h.lobbyMu.Unlock() // Unlock lobbyMu
-		h.gameMu.Unlock()  // Unlock gameMu
+// handleSpectateGame lets a client watch a running game by its code.
+func (h *Hub) handleSpectateGame(client *Client, msg protocol.Message) {
+	h.clientMu.RLock()
+	_, alreadyInGame := h.clientToGame[client]
+	h.clientMu.RUnlock()
+	if alreadyInGame {
+		log.Printf("Client %s tried to spectate but is already associated with a game.", client.ID)
+		h.sendJoinError(client, "Already in a game or lobby.")
+		return
+	}
 
-		log.Printf("Game instance created for code %s with ID %s. Players: %v", gameCode, newGame.ID, playerNames(finalLobby))
+	var payload protocol.SpectateGamePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling spectate_game payload from client %s: %v", client.ID, err)
+		h.sendJoinError(client, "Invalid spectate_game message format.")
+		return
+	}
+	gameCode := strings.ToUpper(payload.GameCode) // Normalize game code
 
-		// Start the game loop/first round in a goroutine
-		// This function should handle sending game_start, deal_hand, etc.
-		go newGame.StartGameLoop(h.sendMessageToClient) // Pass the callback
+	h.gameMu.RLock()
+	gameInstance, gameExists := h.games[gameCode]
+	h.gameMu.RUnlock()
+	if !gameExists {
+		log.Printf("Client %s tried to spectate game %s, which has not started or does not exist.", client.ID, gameCode)
+		h.sendJoinError(client, "Game not found or not started yet.")
+		return
 	}
+
+	h.clientMu.Lock()
+	if client.AccountID == "" {
+		client.Name = payload.Name // Registered players watch under their username
+	}
+	client.Spectator = true
+	h.clientToGame[client] = gameCode
+	h.clientMu.Unlock()
+
+	if !gameInstance.AddSpectator(client.ID) {
+		h.clientMu.Lock()
+		delete(h.clientToGame, client)
+		client.Spectator = false
+		h.clientMu.Unlock()
+		h.sendJoinError(client, "Game is already over.")
+		return
+	}
+	log.Printf("Client %s (%s) is spectating game %s.", client.ID, client.Name, gameCode)
+	h.sendChatHistory(client, gameCode)
 }
 
 // handleGameAction forwards actions like play_card or declare to the correct game instance.
 func (h *Hub) handleGameAction(client *Client, msg protocol.Message) {
+	if client.Spectator {
+		log.Printf("Spectator %s tried to send '%s'.", client.ID, msg.Type)
+		h.sendErrorToClient(client, "Spectators cannot play.")
+		return
+	}
+
 	h.clientMu.RLock()
 	gameCode, inGame := h.clientToGame[client]
 	h.clientMu.RUnlock()
@@ -406,23 +895,88 @@ func playerNames(players []*Client) []string {
 }
 
 // Helper to convert server Clients to game Players
-func convertClientsToGamePlayers(clients []*Client) [4]*shared.Player {
-	if len(clients) != 4 {
-		log.Printf("Error: convertClientsToGamePlayers called with %d clients, expected 4", len(clients))
-		return [4]*shared.Player{} // Return empty array or handle error appropriately
-	}
-	var gamePlayers [4]*shared.Player
+func convertClientsToGamePlayers(clients []*Client) []*shared.Player {
+	gamePlayers := make([]*shared.Player, len(clients))
 	for i, c := range clients {
 		if c == nil {
 			log.Printf("Error: Nil client found at index %d during conversion", i)
 			// Handle error: return empty, panic, or skip? Returning empty for now.
-			return [4]*shared.Player{}
+			return nil
 		}
 		gamePlayers[i] = shared.NewPlayer(c.ID, c.Name, c.DesiredTeam)
+		gamePlayers[i].AccountID = c.AccountID
 	}
 	return gamePlayers
 }
 
+// issueSession creates a reconnect token for the client's seat and sends it to the client.
+func (h *Hub) issueSession(client *Client, gameCode string) {
+	s := &session{
+		token:     uuid.NewString(),
+		playerID:  client.ID,
+		name:      client.Name,
+		accountID: client.AccountID,
+		gameCode:  gameCode,
+	}
+	h.sessionMu.Lock()
+	h.sessions[s.token] = s
+	h.sessionMu.Unlock()
+	client.SessionToken = s.token
+
+	payload := protocol.SessionPayload{Token: s.token, PlayerID: s.playerID, GameCode: gameCode}
+	msgBytes, _ := protocol.NewMessage("session", payload)
+	h.sendMessageToClient(client.ID, msgBytes)
+}
+
+// revokeSession forgets a session token, e.g. when its player leaves a lobby.
+func (h *Hub) revokeSession(token string) {
+	h.sessionMu.Lock()
+	defer h.sessionMu.Unlock()
+	if s, ok := h.sessions[token]; ok {
+		if s.pending != nil {
+			s.pending.Stop()
+		}
+		delete(h.sessions, token)
+	}
+}
+
+// holdSeat pauses the game for a disconnected player and forfeits it if they don't rejoin in time.
+func (h *Hub) holdSeat(client *Client, gameInstance *game.Game) {
+	h.sessionMu.Lock()
+	s, ok := h.sessions[client.SessionToken]
+	h.sessionMu.Unlock()
+
+	grace := h.config.ReconnectGracePeriod
+	if !ok || grace <= 0 {
+		gameInstance.ForfeitPlayer(client.ID)
+		h.revokeSession(client.SessionToken)
+		return
+	}
+	if !gameInstance.HandlePlayerDisconnect(client.ID, grace) {
+		h.revokeSession(client.SessionToken)
+		return
+	}
+
+	h.sessionMu.Lock()
+	var timer *time.Timer
+	timer = time.AfterFunc(grace, func() {
+		h.sessionMu.Lock()
+		current, stillHeld := h.sessions[s.token]
+		expired := stillHeld && current.pending == timer
+		if expired {
+			delete(h.sessions, s.token)
+		}
+		h.sessionMu.Unlock()
+
+		if expired {
+			log.Printf("Player %s did not rejoin game %s in time.", s.playerID, s.gameCode)
+			gameInstance.ForfeitPlayer(s.playerID)
+		}
+	})
+	s.pending = timer
+	h.sessionMu.Unlock()
+}
+
 // sendMessageToClient allows the game logic to send messages back via the hub/client.
 // This is passed as a callback to the game instance.
 func (h *Hub) sendMessageToClient(clientID string, message []byte) {
@@ -472,13 +1026,13 @@ func (h *Hub) broadcastToLobby(gameCode string, message []byte) {
 		return
 	}
 	// Create a copy of the slice to avoid holding lock during send
-	clientsToSend := make([]*Client, len(lobby))
-	copy(clientsToSend, lobby)
+	clientsToSend := make([]*Client, len(lobby.Clients))
+	copy(clientsToSend, lobby.Clients)
 	h.lobbyMu.RUnlock()
 
 	log.Printf("Broadcasting message to %d clients in lobby %s", len(clientsToSend), gameCode)
 	for _, client := range clientsToSend {
-		if client != nil {
+		if client != nil && !client.Bot {
 			select {
 			case client.send <- message:
 			default:
@@ -498,14 +1052,34 @@ func (h *Hub) broadcastToLobby(gameCode string, message []byte) {
 }
 
 // broadcastLobbyUpdate sends the current list of players in the lobby.
-func (h *Hub) broadcastLobbyUpdate(gameCode string, lobby []*Client) {
-	playerInfos := make([]protocol.PlayerInfo, len(lobby))
-	for i, c := range lobby {
+// Must be called without holding lobbyMu.
+func (h *Hub) broadcastLobbyUpdate(gameCode string) {
+	h.lobbyMu.RLock()
+	lobby, exists := h.lobbies[gameCode]
+	if !exists {
+		h.lobbyMu.RUnlock()
+		return
+	}
+	var playerInfos []protocol.PlayerInfo
+	seats := make([]protocol.LobbySeat, len(lobby.Seats))
+	for i, c := range lobby.Seats {
+		seats[i] = protocol.LobbySeat{Seat: i, Team: lobby.SeatTeam(i)}
 		if c != nil {
-			playerInfos[i] = protocol.PlayerInfo{ID: c.ID, Name: c.Name, Position: i} // Use index as position
+			seats[i].PlayerID = c.ID
+			playerInfos = append(playerInfos, protocol.PlayerInfo{ID: c.ID, Name: c.Name, Position: i, Team: lobby.SeatTeam(i), IsBot: c.Bot})
 		}
 	}
-	payload := protocol.LobbyUpdatePayload{Players: playerInfos}
+	payload := protocol.LobbyUpdatePayload{
+		Players:     playerInfos,
+		PlayerCount: lobby.PlayerCount,
+		Seats:       seats,
+		SeatsLocked: lobby.SeatsLocked,
+	}
+	if host := lobby.Host(); host != nil {
+		payload.HostID = host.ID
+	}
+	h.lobbyMu.RUnlock()
+
 	msgBytes, err := protocol.NewMessage("lobby_update", payload)
 	if err != nil {
 		log.Printf("Error creating lobby_update message for lobby %s: %v", gameCode, err)
@@ -536,4 +1110,3 @@ func (h *Hub) sendJoinError(client *Client, errorMsg string) {
 	}
 	h.sendMessageToClient(client.ID, msgBytes)
 }
-
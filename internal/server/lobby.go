@@ -0,0 +1,159 @@
+package server
+
+import (
+	"math/rand"
+
+	"tressette-game/internal/game"
+	"tressette-game/internal/shared"
+)
+
+// Lobby is a table that is still gathering players.
+type Lobby struct {
+	Clients     []*Client     // Humans and bots, in join order
+	Seats       []*Client     // Who sits where, by seat; nil for a free seat
+	SeatsLocked bool          // The host froze the seating
+	PlayerCount int           // Seats to fill before the game starts (2, 3 or 4)
+	Settings    game.Settings // Table options passed on to the game
+	BestOf      int           // Games in the match the table plays
+	Public      bool          // Listed in the lobby browser
+	Reserved    []string      // Join token of each seat at a tournament table, nil elsewhere
+
+	swapRequests map[string]string // Player ID asking for a swap to the player ID they want to swap with
+}
+
+// newLobby opens a lobby with every seat free.
+func newLobby(playerCount int, bestOf int, settings game.Settings) *Lobby {
+	return &Lobby{
+		Seats:        make([]*Client, playerCount),
+		PlayerCount:  playerCount,
+		Settings:     settings,
+		BestOf:       bestOf,
+		swapRequests: make(map[string]string),
+	}
+}
+
+// Host returns the first human in the lobby, who manages its settings and bots.
+func (l *Lobby) Host() *Client {
+	for _, c := range l.Clients {
+		if c != nil && !c.Bot {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsFull reports whether every seat is taken.
+func (l *Lobby) IsFull() bool {
+	return len(l.Clients) >= l.PlayerCount
+}
+
+// SeatTeam returns the number of the team playing from the given seat.
+func (l *Lobby) SeatTeam(seat int) shared.TeamEnum {
+	return shared.TeamEnum(game.SeatTeam(seat, l.PlayerCount, l.Settings.Rules.Mode()))
+}
+
+// seatOf returns the seat the client sits in, or -1.
+func (l *Lobby) seatOf(client *Client) int {
+	for seat, c := range l.Seats {
+		if c == client {
+			return seat
+		}
+	}
+	return -1
+}
+
+// freeSeat returns the first free seat of the given team, or any free seat if
+// the team is full and strict is false. Returns -1 if there is none.
+func (l *Lobby) freeSeat(team shared.TeamEnum, strict bool) int {
+	for seat, c := range l.Seats {
+		if c == nil && l.SeatTeam(seat) == team {
+			return seat
+		}
+	}
+	if strict {
+		return -1
+	}
+	for seat, c := range l.Seats {
+		if c == nil {
+			return seat
+		}
+	}
+	return -1
+}
+
+// add seats a client, on its desired team when a seat there is free. At a
+// tournament table the client takes the seat its join token is for. Returns
+// false if there is no seat for it.
+func (l *Lobby) add(client *Client, seatToken string) bool {
+	seat := l.freeSeat(client.DesiredTeam, false)
+	if l.Reserved != nil {
+		seat = l.reservedSeat(seatToken)
+	}
+	if seat < 0 {
+		return false
+	}
+	l.Clients = append(l.Clients, client)
+	l.sit(client, seat)
+	return true
+}
+
+// reservedSeat returns the free seat the join token is for, or -1.
+func (l *Lobby) reservedSeat(seatToken string) int {
+	for seat, token := range l.Reserved {
+		if seatToken != "" && token == seatToken && l.Seats[seat] == nil {
+			return seat
+		}
+	}
+	return -1
+}
+
+// sit puts a client in the given seat, which must be free, and updates the team it plays for.
+func (l *Lobby) sit(client *Client, seat int) {
+	if old := l.seatOf(client); old >= 0 {
+		l.Seats[old] = nil
+	}
+	l.Seats[seat] = client
+	client.DesiredTeam = l.SeatTeam(seat)
+}
+
+// swap exchanges the seats of two seated clients and drops their pending requests.
+func (l *Lobby) swap(a, b *Client) {
+	seatA, seatB := l.seatOf(a), l.seatOf(b)
+	l.Seats[seatA], l.Seats[seatB] = b, a
+	a.DesiredTeam = l.SeatTeam(seatB)
+	b.DesiredTeam = l.SeatTeam(seatA)
+	delete(l.swapRequests, a.ID)
+	delete(l.swapRequests, b.ID)
+}
+
+// shuffle deals the players out to random seats, changing who partners whom.
+func (l *Lobby) shuffle() {
+	rand.Shuffle(len(l.Seats), func(i, j int) {
+		l.Seats[i], l.Seats[j] = l.Seats[j], l.Seats[i]
+	})
+	for seat, c := range l.Seats {
+		if c != nil {
+			c.DesiredTeam = l.SeatTeam(seat)
+		}
+	}
+	l.swapRequests = make(map[string]string)
+}
+
+// remove drops a client from the lobby and frees its seat. Returns false if it wasn't there.
+func (l *Lobby) remove(client *Client) bool {
+	if seat := l.seatOf(client); seat >= 0 {
+		l.Seats[seat] = nil
+	}
+	for from, to := range l.swapRequests {
+		if from == client.ID || to == client.ID {
+			delete(l.swapRequests, from)
+		}
+	}
+	for i, c := range l.Clients {
+		if c == client {
+			l.Clients = append(l.Clients[:i], l.Clients[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,344 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"tressette-game/internal/database"
+	"tressette-game/internal/game"
+	"tressette-game/internal/protocol"
+	"tressette-game/internal/shared"
+
+	"github.com/google/uuid"
+)
+
+// A table stays together after a game: everyone votes on a rematch, and once
+// all players accept the next game starts with the same seats. Tables created
+// with a best-of setting play a series; the series is over once a team has won
+// more than half of the games, and if it is still level after BestOf games
+// (three players can split the games) it goes on until one team leads alone.
+
+// Match is the series a table is playing. A single game is a best-of-1 series.
+type Match struct {
+	ID          string
+	BestOf      int
+	Settings    game.Settings        // Table options every game of the series is played with
+	Wins        []int                // Games won, by team number - 1
+	Games       []database.MatchGame // Games finished in the series, in order
+	Finished    bool                 // A team won the series
+	WinningTeam int                  // Team number of the series winner, 0 until finished
+	created     time.Time
+	accepted    map[string]bool // Players asked to accept the next game and whether they did, nil outside a vote
+}
+
+// newMatch starts a series with no games played.
+func newMatch(bestOf int, settings game.Settings) *Match {
+	return &Match{
+		ID:       uuid.NewString(),
+		BestOf:   bestOf,
+		Settings: settings,
+		created:  time.Now(),
+	}
+}
+
+// validBestOf reports whether a table may play a series of that length.
+func validBestOf(bestOf int) bool {
+	return bestOf == 1 || bestOf == 3 || bestOf == 5
+}
+
+// record adds a finished game to the series and checks whether it is decided.
+func (m *Match) record(outcome game.Outcome) {
+	m.Games = append(m.Games, database.MatchGame{GameID: outcome.GameID, WinningTeam: outcome.WinningTeam})
+	for len(m.Wins) < len(outcome.Scores) {
+		m.Wins = append(m.Wins, 0)
+	}
+	m.Wins[outcome.WinningTeam-1]++
+
+	if outcome.LeaverID != "" {
+		// Nobody can play on without the player who left
+		m.Finished = true
+		m.WinningTeam = outcome.WinningTeam
+		return
+	}
+
+	leader, tied := -1, false
+	for i, wins := range m.Wins {
+		switch {
+		case leader == -1 || wins > m.Wins[leader]:
+			leader, tied = i, false
+		case wins == m.Wins[leader]:
+			tied = true
+		}
+	}
+	if m.Wins[leader] > m.BestOf/2 || (len(m.Games) >= m.BestOf && !tied) {
+		m.Finished = true
+		m.WinningTeam = leader + 1
+	}
+}
+
+// tiebreak reports whether the series is level after BestOf games and goes on.
+func (m *Match) tiebreak() bool {
+	return !m.Finished && len(m.Games) >= m.BestOf
+}
+
+// pending lists the players who have not accepted the next game yet.
+func (m *Match) pending() []string {
+	ids := []string{}
+	for id, accepted := range m.accepted {
+		if !accepted {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// payload describes the series and the vote for the next game.
+func (m *Match) payload() protocol.MatchUpdatePayload {
+	return protocol.MatchUpdatePayload{
+		MatchID:          m.ID,
+		BestOf:           m.BestOf,
+		Wins:             append([]int{}, m.Wins...),
+		GamesPlayed:      len(m.Games),
+		Tiebreak:         m.tiebreak(),
+		Finished:         m.Finished,
+		WinningTeam:      m.WinningTeam,
+		PendingPlayerIDs: m.pending(),
+	}
+}
+
+// result builds the database record of the series.
+func (m *Match) result(status string) database.MatchResult {
+	return database.MatchResult{
+		ID:          m.ID,
+		CreatedAt:   m.created.Format(time.RFC3339),
+		BestOf:      m.BestOf,
+		Ruleset:     m.Settings.Rules.Name(),
+		Status:      status,
+		WinningTeam: m.WinningTeam,
+		Games:       append([]database.MatchGame{}, m.Games...),
+	}
+}
+
+// saveMatch stores a series once it is over. Single games are only stored as results.
+func (h *Hub) saveMatch(match *Match, status string) {
+	if match.BestOf <= 1 || len(match.Games) == 0 {
+		return
+	}
+	if err := h.db.InsertMatch(match.result(status)); err != nil {
+		log.Printf("Error saving match %s: %v", match.ID, err)
+	}
+}
+
+// newTableGame creates a game for the table with the given code and hooks it up
+// to the table's match. Bots get their agents back.
+func (h *Hub) newTableGame(gameCode string, players []*shared.Player, bots map[string]bool, settings game.Settings) *game.Game {
+	newGame := game.NewGame(players, settings, h.db)
+	for _, p := range players {
+		if bots[p.ID] {
+			newGame.AttachAgent(p.ID, game.NewHeuristicBot(p.ID))
+		}
+	}
+	newGame.OnGameOver(func(outcome game.Outcome) {
+		h.handleGameOver(gameCode, outcome)
+	})
+	return newGame
+}
+
+// handleGameOver records a finished game in the table's series and opens the
+// vote for the next game. A forfeit ends the series and breaks up the table.
+// Tournament tables play a single game and hand it to their tournament.
+func (h *Hub) handleGameOver(gameCode string, outcome game.Outcome) {
+	if h.finishTournamentGame(gameCode, outcome) {
+		return
+	}
+
+	h.gameMu.RLock()
+	gameInstance, gameExists := h.games[gameCode]
+	h.gameMu.RUnlock()
+
+	h.matchMu.Lock()
+	match, ok := h.matches[gameCode]
+	if !ok || !gameExists || gameInstance.ID != outcome.GameID {
+		h.matchMu.Unlock()
+		return
+	}
+	match.record(outcome)
+	log.Printf("Game %s (%s) over. Match %s: wins %v, finished %t.", gameCode, outcome.GameID, match.ID, match.Wins, match.Finished)
+
+	if outcome.LeaverID != "" {
+		h.saveMatch(match, database.MatchForfeit)
+		payload := match.payload()
+		h.matchMu.Unlock()
+
+		msg, _ := protocol.NewMessage("match_update", payload)
+		h.broadcastToTable(gameCode, msg)
+		h.closeTable(gameCode, outcome.LeaverID)
+		return
+	}
+
+	if match.Finished {
+		h.saveMatch(match, database.MatchDecided)
+	}
+	match.accepted = make(map[string]bool)
+	for _, p := range gameInstance.Seating() {
+		if !gameInstance.IsAgent(p.ID) {
+			match.accepted[p.ID] = false
+		}
+	}
+	payload := match.payload()
+	h.matchMu.Unlock()
+
+	msg, _ := protocol.NewMessage("match_update", payload)
+	h.broadcastToTable(gameCode, msg)
+}
+
+// handleRematch records a player's answer to the vote for the next game.
+func (h *Hub) handleRematch(client *Client, msg protocol.Message) {
+	var payload protocol.RematchPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling rematch payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid rematch message format.")
+		return
+	}
+
+	h.clientMu.RLock()
+	gameCode, inGame := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.matchMu.Lock()
+	match, ok := h.matches[gameCode]
+	if !inGame || !ok || match.accepted == nil {
+		h.matchMu.Unlock()
+		h.sendErrorToClient(client, "There is no rematch to vote on.")
+		return
+	}
+	if _, seated := match.accepted[client.ID]; !seated {
+		h.matchMu.Unlock()
+		h.sendErrorToClient(client, "Only the players can vote on a rematch.")
+		return
+	}
+
+	if !payload.Accept {
+		h.matchMu.Unlock()
+		log.Printf("Client %s declined the rematch at table %s.", client.ID, gameCode)
+		h.leaveTable(gameCode, client.ID)
+		return
+	}
+
+	match.accepted[client.ID] = true
+	log.Printf("Client %s accepted the rematch at table %s.", client.ID, gameCode)
+	if len(match.pending()) > 0 {
+		update := match.payload()
+		h.matchMu.Unlock()
+		updateMsg, _ := protocol.NewMessage("match_update", update)
+		h.broadcastToTable(gameCode, updateMsg)
+		return
+	}
+
+	match.accepted = nil
+	if match.Finished {
+		// The series is over, the rematch starts a new one
+		*match = *newMatch(match.BestOf, match.Settings)
+	}
+	settings := match.Settings
+	h.matchMu.Unlock()
+
+	h.startNextGame(gameCode, settings)
+}
+
+// startNextGame replaces the table's finished game with a new one with the same seats.
+func (h *Hub) startNextGame(gameCode string, settings game.Settings) {
+	h.gameMu.Lock()
+	oldGame, ok := h.games[gameCode]
+	if !ok {
+		h.gameMu.Unlock()
+		return
+	}
+	players := oldGame.Seating()
+	bots := make(map[string]bool)
+	for _, p := range players {
+		bots[p.ID] = oldGame.IsAgent(p.ID)
+	}
+	newGame := h.newTableGame(gameCode, players, bots, settings)
+	h.games[gameCode] = newGame
+	h.gameMu.Unlock()
+
+	// Spectators keep watching the table
+	h.clientMu.RLock()
+	for c, code := range h.clientToGame {
+		if code == gameCode && c.Spectator {
+			newGame.AddSpectator(c.ID)
+		}
+	}
+	h.clientMu.RUnlock()
+
+	log.Printf("Rematch at table %s: game %s starting.", gameCode, newGame.ID)
+	go newGame.StartGameLoop(h.sendMessageToClient)
+}
+
+// leaveTable breaks up a table voting on its next game because a player left
+// or declined. A series that wasn't decided is stored as abandoned.
+func (h *Hub) leaveTable(gameCode string, playerID string) {
+	h.matchMu.Lock()
+	if match, ok := h.matches[gameCode]; ok && !match.Finished {
+		h.saveMatch(match, database.MatchAbandoned)
+	}
+	h.matchMu.Unlock()
+	h.closeTable(gameCode, playerID)
+}
+
+// closeTable breaks up a table whose game is over: its players and spectators
+// are told and freed to create or join another game.
+func (h *Hub) closeTable(gameCode string, playerID string) {
+	h.matchMu.Lock()
+	delete(h.matches, gameCode)
+	h.matchMu.Unlock()
+
+	h.gameMu.Lock()
+	delete(h.games, gameCode)
+	h.gameMu.Unlock()
+
+	var members []*Client
+	h.clientMu.Lock()
+	for c, code := range h.clientToGame {
+		if code == gameCode {
+			members = append(members, c)
+			delete(h.clientToGame, c)
+			c.Spectator = false
+		}
+	}
+	h.clientMu.Unlock()
+
+	msg, _ := protocol.NewMessage("table_closed", protocol.TableClosedPayload{PlayerID: playerID})
+	for _, c := range members {
+		h.revokeSession(c.SessionToken)
+		h.sendMessageToClient(c.ID, msg)
+	}
+	h.closeChat(gameCode)
+	log.Printf("Table %s closed.", gameCode)
+}
+
+// inRematchVote reports whether the table is voting on its next game.
+func (h *Hub) inRematchVote(gameCode string) bool {
+	h.matchMu.Lock()
+	defer h.matchMu.Unlock()
+	match, ok := h.matches[gameCode]
+	return ok && match.accepted != nil
+}
+
+// broadcastToTable sends a message to the players and spectators of a game.
+func (h *Hub) broadcastToTable(gameCode string, message []byte) {
+	var ids []string
+	h.clientMu.RLock()
+	for c, code := range h.clientToGame {
+		if code == gameCode && !c.Bot {
+			ids = append(ids, c.ID)
+		}
+	}
+	h.clientMu.RUnlock()
+
+	for _, id := range ids {
+		h.sendMessageToClient(id, message)
+	}
+}
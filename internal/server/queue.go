@@ -0,0 +1,274 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+
+	"tressette-game/internal/game"
+	"tressette-game/internal/protocol"
+)
+
+// Players who don't have a table can ask for a quick match: they wait in a
+// queue until there are enough of them, and are then seated at a new
+// four-player table with the default rules, which starts at once. Players with
+// similar ratings are grouped together, and the table is ranked when everyone
+// at it is registered.
+
+const (
+	QuickMatchPlayers    = game.FourPlayers // Players seated at a quick-match table
+	QuickMatchPointsGoal = 51               // Points goal of a quick-match game
+)
+
+// handleQuickMatch puts a player in the quick-match queue and starts a table
+// once enough players are waiting.
+func (h *Hub) handleQuickMatch(client *Client, msg protocol.Message) {
+	var payload protocol.QuickMatchPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling quick_match payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid quick_match message format.")
+		return
+	}
+	name, err := h.playerName(client, payload.Name)
+	if err != nil {
+		log.Printf("Client %s asked for a quick match with name '%s': %v", client.ID, payload.Name, err)
+		h.sendErrorToClient(client, err.Error())
+		return
+	}
+	payload.Name = name
+
+	h.clientMu.Lock()
+	_, alreadyInGame := h.clientToGame[client]
+	if !alreadyInGame {
+		client.Name = payload.Name
+	}
+	h.clientMu.Unlock()
+	if alreadyInGame {
+		log.Printf("Client %s asked for a quick match but is already associated with a game.", client.ID)
+		h.sendErrorToClient(client, "Already in a game or lobby.")
+		return
+	}
+
+	h.queueMu.Lock()
+	for _, c := range h.queue {
+		if c == client {
+			h.queueMu.Unlock()
+			h.sendErrorToClient(client, "You are already waiting for a quick match.")
+			return
+		}
+	}
+	h.queue = append(h.queue, client)
+	group := h.nextQuickMatch()
+	h.queueMu.Unlock()
+
+	log.Printf("Client %s (%s) is waiting for a quick match.", client.ID, client.Name)
+	if group != nil {
+		h.startQuickMatch(group)
+	}
+	h.broadcastQueueUpdate()
+}
+
+// handleLeaveQueue takes a player out of the quick-match queue.
+func (h *Hub) handleLeaveQueue(client *Client) {
+	if !h.leaveQueue(client) {
+		h.sendErrorToClient(client, "You are not waiting for a quick match.")
+	}
+}
+
+// leaveQueue takes a client out of the quick-match queue and tells it and the
+// players still waiting. Returns false if it wasn't queued.
+func (h *Hub) leaveQueue(client *Client) bool {
+	h.queueMu.Lock()
+	removed := false
+	for i, c := range h.queue {
+		if c == client {
+			h.queue = append(h.queue[:i], h.queue[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	h.queueMu.Unlock()
+	if !removed {
+		return false
+	}
+
+	log.Printf("Client %s left the quick-match queue.", client.ID)
+	msg, _ := protocol.NewMessage("queue_update", protocol.QueueUpdatePayload{Queued: false})
+	h.sendMessageToClient(client.ID, msg)
+	h.broadcastQueueUpdate()
+	return true
+}
+
+// nextQuickMatch takes the players for the next table out of the queue, or
+// returns nil if there aren't enough yet. The table is built around the player
+// who has waited longest, with the waiting players whose ratings are closest to
+// theirs. Players who found a table in the meantime are dropped, and names must
+// be unique at the table, so if the longest waiting player's name leaves too few
+// others, the table is built around the next player in line instead.
+// Assumes queueMu is held.
+func (h *Hub) nextQuickMatch() []*Client {
+	h.clientMu.RLock()
+	waiting := h.queue[:0]
+	for _, c := range h.queue {
+		if _, inGame := h.clientToGame[c]; !inGame {
+			waiting = append(waiting, c)
+		}
+	}
+	h.queue = waiting
+	h.clientMu.RUnlock()
+	if len(h.queue) < QuickMatchPlayers {
+		return nil
+	}
+
+	ratings := h.ratings(h.queue)
+	for _, anchor := range h.queue {
+		group := h.quickMatchAround(anchor, ratings)
+		if group == nil {
+			continue
+		}
+
+		rest := make([]*Client, 0, len(h.queue)-len(group))
+		for _, c := range h.queue {
+			if !contains(group, c) {
+				rest = append(rest, c)
+			}
+		}
+		h.queue = rest
+		return balanceSides(group, ratings)
+	}
+	return nil
+}
+
+// quickMatchAround picks the anchor and the waiting players with the closest
+// ratings and distinct names, or returns nil if there aren't enough of them.
+// Assumes queueMu is held.
+func (h *Hub) quickMatchAround(anchor *Client, ratings map[*Client]int) []*Client {
+	candidates := []*Client{}
+	for _, c := range h.queue {
+		if c != anchor {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return abs(ratings[candidates[i]]-ratings[anchor]) < abs(ratings[candidates[j]]-ratings[anchor])
+	})
+
+	group := []*Client{anchor}
+	names := map[string]bool{anchor.Name: true}
+	for _, c := range candidates {
+		if len(group) == QuickMatchPlayers {
+			break
+		}
+		if !names[c.Name] {
+			group = append(group, c)
+			names[c.Name] = true
+		}
+	}
+	if len(group) < QuickMatchPlayers {
+		return nil
+	}
+	return group
+}
+
+// balanceSides orders four players by seat so the best and the weakest play
+// together against the two in between.
+func balanceSides(group []*Client, ratings map[*Client]int) []*Client {
+	sorted := append([]*Client{}, group...)
+	sort.SliceStable(sorted, func(i, j int) bool { return ratings[sorted[i]] > ratings[sorted[j]] })
+	// Seats 0 and 2 play together, as do seats 1 and 3
+	return []*Client{sorted[0], sorted[1], sorted[3], sorted[2]}
+}
+
+// ratings looks up the rating of every client; guests and players without a
+// ranked game count as InitialRating.
+func (h *Hub) ratings(clients []*Client) map[*Client]int {
+	var ids []string
+	for _, c := range clients {
+		if c.AccountID != "" {
+			ids = append(ids, c.AccountID)
+		}
+	}
+	stored, err := h.db.GetRatings(ids)
+	if err != nil {
+		log.Printf("Error loading ratings for matchmaking: %v", err)
+	}
+
+	ratings := make(map[*Client]int, len(clients))
+	for _, c := range clients {
+		ratings[c] = game.InitialRating
+		if r, ok := stored[c.AccountID]; ok {
+			ratings[c] = r.Rating
+		}
+	}
+	return ratings
+}
+
+// startQuickMatch seats a group from the queue at a new table, in the order
+// given, and starts its game. The game is ranked when every player is registered.
+func (h *Hub) startQuickMatch(group []*Client) {
+	gameCode := h.generateGameCode()
+	rules, _ := game.LookupRuleset(game.DefaultRuleset)
+	ranked := true
+	for _, c := range group {
+		ranked = ranked && c.AccountID != ""
+	}
+	lobby := newLobby(QuickMatchPlayers, 1, game.Settings{
+		TargetScore:  QuickMatchPointsGoal,
+		MortoRule:    game.MortoAside,
+		Rules:        rules,
+		Rounding:     game.RoundTruncate,
+		Ranked:       ranked,
+		TrickPause:   h.config.TrickPause,
+		ReadyTimeout: h.config.ReadyTimeout,
+	})
+
+	h.clientMu.Lock()
+	for seat, c := range group {
+		c.DesiredTeam = lobby.SeatTeam(seat)
+		lobby.add(c, "")
+		h.clientToGame[c] = gameCode
+	}
+	h.clientMu.Unlock()
+
+	h.lobbyMu.Lock()
+	h.lobbies[gameCode] = lobby
+	h.lobbyMu.Unlock()
+
+	log.Printf("Quick match %s seats %v (ranked: %t).", gameCode, playerNames(group), ranked)
+	found, _ := protocol.NewMessage("quick_match_found", protocol.QuickMatchFoundPayload{GameCode: gameCode})
+	for _, c := range group {
+		h.sendMessageToClient(c.ID, found)
+		h.issueSession(c, gameCode)
+	}
+	h.startGameIfFull(gameCode)
+}
+
+// broadcastQueueUpdate tells every queued player how many are waiting.
+func (h *Hub) broadcastQueueUpdate() {
+	h.queueMu.Lock()
+	waiting := make([]*Client, len(h.queue))
+	copy(waiting, h.queue)
+	h.queueMu.Unlock()
+
+	msg, _ := protocol.NewMessage("queue_update", protocol.QueueUpdatePayload{Queued: true, Waiting: len(waiting)})
+	for _, c := range waiting {
+		h.sendMessageToClient(c.ID, msg)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// contains reports whether the client is in the list.
+func contains(clients []*Client, client *Client) bool {
+	for _, c := range clients {
+		if c == client {
+			return true
+		}
+	}
+	return false
+}
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 
 	"tressette-game/internal/database"
+	"tressette-game/internal/game"
+	"tressette-game/internal/protocol"
 )
 
-func HandleRoutes(db *database.Service) {
+func HandleRoutes(db *database.Service, hub *Hub) {
 	http.HandleFunc("/api/results/player/{name}", func(w http.ResponseWriter, r *http.Request) {
 		GetResultsByPlayerHandler(db, w, r)
 	})
@@ -21,6 +24,100 @@ func HandleRoutes(db *database.Service) {
 	})
 
 	log.Println("Registerd route: /api/results")
+
+	http.HandleFunc("/api/rulesets", GetRulesetsHandler)
+
+	log.Println("Registered route: /api/rulesets")
+
+	http.HandleFunc("/api/games/{id}/replay", func(w http.ResponseWriter, r *http.Request) {
+		GetReplayHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/games/{id}/replay")
+
+	http.HandleFunc("/api/games/{id}/scoresheet", func(w http.ResponseWriter, r *http.Request) {
+		GetScoreSheetHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/games/{id}/scoresheet")
+
+	http.HandleFunc("/api/matches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		GetMatchHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/matches/{id}")
+
+	http.HandleFunc("/api/lobbies", func(w http.ResponseWriter, r *http.Request) {
+		GetLobbiesHandler(hub, w, r)
+	})
+
+	log.Println("Registered route: /api/lobbies")
+
+	http.HandleFunc("/api/accounts", func(w http.ResponseWriter, r *http.Request) {
+		RegisterHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/accounts")
+
+	http.HandleFunc("/api/login", func(w http.ResponseWriter, r *http.Request) {
+		LoginHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/login")
+
+	http.HandleFunc("/api/logout", func(w http.ResponseWriter, r *http.Request) {
+		LogoutHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/logout")
+
+	http.HandleFunc("/api/results/account/{username}", func(w http.ResponseWriter, r *http.Request) {
+		GetResultsByAccountHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/results/account/{username}")
+
+	http.HandleFunc("/api/leaderboard", func(w http.ResponseWriter, r *http.Request) {
+		GetLeaderboardHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/leaderboard")
+
+	http.HandleFunc("/api/ratings/{username}", func(w http.ResponseWriter, r *http.Request) {
+		GetRatingHistoryHandler(db, w, r)
+	})
+
+	log.Println("Registered route: /api/ratings/{username}")
+
+	http.HandleFunc("/api/tournaments", func(w http.ResponseWriter, r *http.Request) {
+		TournamentsHandler(hub, w, r)
+	})
+
+	log.Println("Registered route: /api/tournaments")
+
+	http.HandleFunc("/api/tournaments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		GetTournamentHandler(hub, w, r)
+	})
+
+	log.Println("Registered route: /api/tournaments/{id}")
+
+	http.HandleFunc("/api/tournaments/{id}/standings", func(w http.ResponseWriter, r *http.Request) {
+		GetStandingsHandler(hub, w, r)
+	})
+
+	log.Println("Registered route: /api/tournaments/{id}/standings")
+
+	http.HandleFunc("/api/tournaments/{id}/pairings", func(w http.ResponseWriter, r *http.Request) {
+		GetPairingsHandler(hub, w, r)
+	})
+
+	log.Println("Registered route: /api/tournaments/{id}/pairings")
+
+	http.HandleFunc("/api/tournaments/{id}/results", func(w http.ResponseWriter, r *http.Request) {
+		RecordResultHandler(hub, w, r)
+	})
+
+	log.Println("Registered route: /api/tournaments/{id}/results")
 }
 
 func GetResultsByPlayerHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
@@ -59,3 +156,191 @@ func GetResultsHandler(db *database.Service, w http.ResponseWriter, r *http.Requ
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(results)
 }
+
+// GetMatchHandler returns a best-of-N series with the IDs of its games, which
+// link to their results, replays and score sheets.
+func GetMatchHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	matchID := r.PathValue("id")
+	if matchID == "" {
+		http.Error(w, "Match ID is required", http.StatusBadRequest)
+		return
+	}
+
+	match, err := db.GetMatch(matchID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Match not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to fetch match", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(match)
+}
+
+// DefaultLeaderboardSize is how many players the leaderboard lists unless ?limit=N asks otherwise.
+const DefaultLeaderboardSize = 50
+
+// GetLeaderboardHandler returns the highest rated players, best first.
+func GetLeaderboardHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	limit := DefaultLeaderboardSize
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	ratings, err := db.GetLeaderboard(limit)
+	if err != nil {
+		http.Error(w, "Failed to fetch leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ratings)
+}
+
+// RatingHistory is a player's current rating and how it got there.
+type RatingHistory struct {
+	database.Rating
+	History []database.RatingChange `json:"history"`
+}
+
+// GetRatingHistoryHandler returns a registered player's rating and its history.
+func GetRatingHistoryHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	account, err := db.GetAccountByUsername(r.PathValue("username"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Account not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to fetch account", http.StatusInternalServerError)
+		return
+	}
+
+	ratings, err := db.GetRatings([]string{account.ID})
+	if err != nil {
+		http.Error(w, "Failed to fetch rating", http.StatusInternalServerError)
+		return
+	}
+	history, err := db.GetRatingHistory(account.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch rating history", http.StatusInternalServerError)
+		return
+	}
+
+	current, ok := ratings[account.ID]
+	if !ok {
+		current = database.Rating{AccountID: account.ID, Username: account.Username, Rating: game.InitialRating}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RatingHistory{Rating: current, History: history})
+}
+
+// GetReplayHandler returns the event log of a finished game. With ?step=N it
+// returns the table as it stood after event N instead, with every hand shown.
+func GetReplayHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	events, ok := loadEventLog(db, w, r)
+	if !ok {
+		return
+	}
+
+	stepParam := r.URL.Query().Get("step")
+	if stepParam == "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+		return
+	}
+
+	step, err := strconv.Atoi(stepParam)
+	if err != nil {
+		http.Error(w, "Step must be a number", http.StatusBadRequest)
+		return
+	}
+	state, err := game.RebuildState(events, step)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// GetScoreSheetHandler returns the round by round score sheet of a finished game.
+func GetScoreSheetHandler(db *database.Service, w http.ResponseWriter, r *http.Request) {
+	events, ok := loadEventLog(db, w, r)
+	if !ok {
+		return
+	}
+
+	sheets := []protocol.RoundScoreSheet{}
+	for _, event := range events {
+		if event.Type == game.EventRoundEnd && event.ScoreSheet != nil {
+			sheets = append(sheets, *event.ScoreSheet)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sheets)
+}
+
+// loadEventLog fetches the event log of the game named in the path, answering
+// the request with an error if it can't.
+func loadEventLog(db *database.Service, w http.ResponseWriter, r *http.Request) ([]game.Event, bool) {
+	gameID := r.PathValue("id")
+	if gameID == "" {
+		http.Error(w, "Game ID is required", http.StatusBadRequest)
+		return nil, false
+	}
+
+	records, err := db.GetEvents(gameID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "No log found for game", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, "Failed to fetch game log", http.StatusInternalServerError)
+		return nil, false
+	}
+	events, err := game.DecodeEvents(records)
+	if err != nil {
+		log.Printf("Log of game %s is corrupt: %v", gameID, err)
+		http.Error(w, "Failed to read game log", http.StatusInternalServerError)
+		return nil, false
+	}
+	return events, true
+}
+
+// RulesetInfo describes a built-in variant that can be picked in create_game.
+type RulesetInfo struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Mode         string `json:"mode"`
+	PlayerCounts []int  `json:"player_counts"`
+}
+
+func GetRulesetsHandler(w http.ResponseWriter, r *http.Request) {
+	var infos []RulesetInfo
+	for _, rules := range game.Rulesets() {
+		info := RulesetInfo{
+			Name:        rules.Name(),
+			Description: rules.Description(),
+			Mode:        string(rules.Mode()),
+		}
+		for _, n := range []int{game.TwoPlayers, game.ThreePlayers, game.FourPlayers} {
+			if rules.SupportsPlayerCount(n) {
+				info.PlayerCounts = append(info.PlayerCounts, n)
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(infos)
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+
+	"tressette-game/internal/protocol"
+)
+
+// Players pick their seats in the lobby: seats alternate between the teams, so
+// the seat decides both the team and the partner. A free seat can be taken
+// directly; taking an occupied one needs a swap both players ask for. The host
+// can lock the seating or deal everyone out to random seats.
+
+// handleChooseSeat moves a player to a free seat.
+func (h *Hub) handleChooseSeat(client *Client, msg protocol.Message) {
+	var payload protocol.ChooseSeatPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling choose_seat payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid choose_seat message format.")
+		return
+	}
+
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.Lock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	if !inLobby || !lobbyExists {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if lobby.SeatsLocked {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "The host has locked the seats.")
+		return
+	}
+	if payload.Seat < 0 || payload.Seat >= len(lobby.Seats) {
+		h.lobbyMu.Unlock()
+		log.Printf("Client %s tried to take invalid seat %d in lobby %s.", client.ID, payload.Seat, gameCode)
+		h.sendErrorToClient(client, "Invalid seat.")
+		return
+	}
+	if lobby.Seats[payload.Seat] != nil {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "That seat is taken. Ask its player to swap instead.")
+		return
+	}
+	lobby.sit(client, payload.Seat)
+	h.lobbyMu.Unlock()
+
+	log.Printf("Client %s moved to seat %d in lobby %s.", client.ID, payload.Seat, gameCode)
+	h.broadcastLobbyUpdate(gameCode)
+}
+
+// handleSwapRequest asks to trade seats with the player in another seat. The
+// seats are swapped once both players have asked, or right away with a bot.
+func (h *Hub) handleSwapRequest(client *Client, msg protocol.Message) {
+	var payload protocol.SwapRequestPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling swap_request payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid swap_request message format.")
+		return
+	}
+
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.Lock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	if !inLobby || !lobbyExists {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if lobby.SeatsLocked {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "The host has locked the seats.")
+		return
+	}
+	if payload.Seat < 0 || payload.Seat >= len(lobby.Seats) {
+		h.lobbyMu.Unlock()
+		log.Printf("Client %s asked to swap with invalid seat %d in lobby %s.", client.ID, payload.Seat, gameCode)
+		h.sendErrorToClient(client, "Invalid seat.")
+		return
+	}
+	other := lobby.Seats[payload.Seat]
+	if other == nil {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "That seat is free, take it instead.")
+		return
+	}
+	if other == client {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are already in that seat.")
+		return
+	}
+
+	if other.Bot || lobby.swapRequests[other.ID] == client.ID {
+		lobby.swap(client, other)
+		h.lobbyMu.Unlock()
+		log.Printf("Clients %s and %s swapped seats in lobby %s.", client.ID, other.ID, gameCode)
+		h.broadcastLobbyUpdate(gameCode)
+		return
+	}
+
+	lobby.swapRequests[client.ID] = other.ID
+	requested := protocol.SwapRequestedPayload{PlayerID: client.ID, Name: client.Name, Seat: lobby.seatOf(client)}
+	h.lobbyMu.Unlock()
+
+	log.Printf("Client %s asked %s to swap seats in lobby %s.", client.ID, other.ID, gameCode)
+	requestedMsg, _ := protocol.NewMessage("swap_requested", requested)
+	h.sendMessageToClient(other.ID, requestedMsg)
+}
+
+// handleLockSeats lets the lobby host freeze or release the seating.
+func (h *Hub) handleLockSeats(client *Client, msg protocol.Message) {
+	var payload protocol.LockSeatsPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		log.Printf("Error unmarshalling lock_seats payload from client %s: %v", client.ID, err)
+		h.sendErrorToClient(client, "Invalid lock_seats message format.")
+		return
+	}
+
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.Lock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	if !inLobby || !lobbyExists {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if lobby.Host() != client {
+		h.lobbyMu.Unlock()
+		log.Printf("Client %s tried to lock the seats of lobby %s but is not the host.", client.ID, gameCode)
+		h.sendErrorToClient(client, "Only the host can lock the seats.")
+		return
+	}
+	if lobby.Reserved != nil {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Seats are fixed at tournament tables.")
+		return
+	}
+	lobby.SeatsLocked = payload.Locked
+	h.lobbyMu.Unlock()
+
+	log.Printf("Client %s set the seats of lobby %s locked: %t.", client.ID, gameCode, payload.Locked)
+	h.broadcastLobbyUpdate(gameCode)
+}
+
+// handleRandomizeSeats lets the lobby host deal the players out to random seats.
+func (h *Hub) handleRandomizeSeats(client *Client) {
+	h.clientMu.RLock()
+	gameCode, inLobby := h.clientToGame[client]
+	h.clientMu.RUnlock()
+
+	h.lobbyMu.Lock()
+	lobby, lobbyExists := h.lobbies[gameCode]
+	if !inLobby || !lobbyExists {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "You are not in a lobby.")
+		return
+	}
+	if lobby.Host() != client {
+		h.lobbyMu.Unlock()
+		log.Printf("Client %s tried to randomize the seats of lobby %s but is not the host.", client.ID, gameCode)
+		h.sendErrorToClient(client, "Only the host can randomize the seats.")
+		return
+	}
+	if lobby.Reserved != nil {
+		h.lobbyMu.Unlock()
+		h.sendErrorToClient(client, "Seats are fixed at tournament tables.")
+		return
+	}
+	lobby.shuffle()
+	h.lobbyMu.Unlock()
+
+	log.Printf("Client %s randomized the seats of lobby %s.", client.ID, gameCode)
+	h.broadcastLobbyUpdate(gameCode)
+}
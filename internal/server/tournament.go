@@ -0,0 +1,528 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tressette-game/internal/database"
+	"tressette-game/internal/game"
+	"tressette-game/internal/tournament"
+
+	"github.com/google/uuid"
+)
+
+// Organizers run tournaments between fixed pairs over REST. For every game of
+// a round the server opens a table whose seats are kept for the two pairs,
+// partners opposite each other. Each seat has a join token that only the
+// organizer sees in the pairings; the players join with the game code and
+// their seat's token, and the game starts once all four are in. Finished games are recorded in the
+// tournament and, when the last game of a round is over, the next round is
+// paired and its tables opened. The organizer settles a table whose game
+// can't be played, and running tournaments reopen their tables after a restart.
+
+const DefaultTournamentPointsGoal = QuickMatchPointsGoal // Points goal unless the organizer sets one
+
+// CreateTournamentRequest is the body of a request to create a tournament.
+type CreateTournamentRequest struct {
+	Name        string            `json:"name"`
+	Format      tournament.Format `json:"format"`
+	Pairs       []tournament.Pair `json:"pairs"`        // In seeding order; IDs are assigned
+	SwissRounds int               `json:"swiss_rounds"` // Swiss only; 0 picks enough rounds for the field
+	PointsGoal  int               `json:"points_goal"`  // 0 for DefaultTournamentPointsGoal
+	Ruleset     string            `json:"ruleset"`      // Must be played by two pairs; empty for the default
+}
+
+// RecordResultRequest is the body of an organizer's request to settle a table
+// whose game can't be played on the server.
+type RecordResultRequest struct {
+	Table    int  `json:"table"`    // As the pairings of the current round number it
+	Winner   int  `json:"winner"`   // Pair ID
+	ScoreA   int  `json:"score_a"`  // Final score of pair A; ignored for a walkover
+	ScoreB   int  `json:"score_b"`  // Final score of pair B; ignored for a walkover
+	Walkover bool `json:"walkover"` // The loser didn't turn up
+}
+
+// hostedTournament is a tournament as the server runs it, with the account of
+// its organizer and the join tokens of its seats. It is stored whole as the
+// tournament record's data.
+type hostedTournament struct {
+	*tournament.Tournament
+	OrganizerID string              `json:"organizer_id"`
+	JoinTokens  map[string][]string `json:"join_tokens"` // Join token of each seat, by game code
+}
+
+// TournamentPairing is a game of a round as the pairings list shows it.
+type TournamentPairing struct {
+	Table    int              `json:"table"`
+	GameCode string           `json:"game_code,omitempty"`
+	PairA    tournament.Pair  `json:"pair_a"`
+	PairB    *tournament.Pair `json:"pair_b"` // nil for a bye
+	Winner   int              `json:"winner"` // Pair ID, 0 until the game is over
+	ScoreA   int              `json:"score_a"`
+	ScoreB   int              `json:"score_b"`
+	GameID   string           `json:"game_id,omitempty"`
+	Forfeit  bool             `json:"forfeit,omitempty"`
+
+	JoinTokens map[string]string `json:"join_tokens,omitempty"` // By player name; only the organizer sees them
+}
+
+// TournamentRound lists the pairings of one round.
+type TournamentRound struct {
+	TournamentID string              `json:"tournament_id"`
+	Round        int                 `json:"round"`
+	Complete     bool                `json:"complete"`
+	Pairings     []TournamentPairing `json:"pairings"`
+}
+
+// createTournament sets up a tournament run by the given account and opens the
+// tables of its first round. The error explains what is wrong with the request.
+func (h *Hub) createTournament(req CreateTournamentRequest, organizerID string) (*hostedTournament, error) {
+	if req.Ruleset == "" {
+		req.Ruleset = game.DefaultRuleset
+	}
+	rules, ok := game.LookupRuleset(req.Ruleset)
+	if !ok {
+		return nil, errors.New("Unknown ruleset")
+	}
+	if !rules.SupportsPlayerCount(game.FourPlayers) || game.TeamCount(game.FourPlayers, rules.Mode()) != 2 {
+		return nil, errors.New("Tournament games are played by two pairs")
+	}
+	if req.PointsGoal == 0 {
+		req.PointsGoal = DefaultTournamentPointsGoal
+	}
+	if req.PointsGoal < 1 || req.PointsGoal > 101 {
+		return nil, errors.New("Invalid points goal")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("Tournament name is required")
+	}
+
+	created, err := tournament.New(uuid.NewString(), req.Name, req.Format, req.Pairs, req.SwissRounds)
+	if err != nil {
+		return nil, err
+	}
+	t := &hostedTournament{Tournament: created, OrganizerID: organizerID, JoinTokens: make(map[string][]string)}
+	t.PointsGoal = req.PointsGoal
+	t.Ruleset = rules.Name()
+	t.CreatedAt = time.Now().Format(time.RFC3339)
+
+	h.tournamentMu.Lock()
+	defer h.tournamentMu.Unlock()
+	h.tournaments[t.ID] = t
+	h.advanceTournament(t)
+	log.Printf("Tournament %s (%s) created: %s, %d pairs.", t.ID, t.Name, t.Format, len(t.Pairs))
+	return t, nil
+}
+
+// advanceTournament pairs the next round once the current one is over and
+// opens its tables, then stores the tournament. Assumes tournamentMu is held.
+func (h *Hub) advanceTournament(t *hostedTournament) {
+	for t.Status == tournament.Running && t.RoundComplete() {
+		games := t.NextRound()
+		if games == nil {
+			log.Printf("Tournament %s finished. Winner: pair %d.", t.ID, t.Winner)
+			break
+		}
+		for _, g := range games {
+			if !g.Bye() {
+				h.openTournamentTable(t, g, h.generateGameCode())
+			}
+		}
+		log.Printf("Tournament %s: round %d paired, %d games.", t.ID, len(t.Rounds), len(games))
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		log.Printf("Error encoding tournament %s: %v", t.ID, err)
+		return
+	}
+	record := database.TournamentRecord{
+		ID:        t.ID,
+		Name:      t.Name,
+		Format:    string(t.Format),
+		Status:    t.Status,
+		CreatedAt: t.CreatedAt,
+		Data:      string(data),
+	}
+	if err := h.db.SaveTournament(record); err != nil {
+		log.Printf("Error saving tournament %s: %v", t.ID, err)
+	}
+}
+
+// openTournamentTable opens the table of a game under the given code, with
+// each seat kept for its player: pair A at seats 0 and 2, pair B at seats 1
+// and 3. A table reopened under its old code keeps its join tokens. Assumes
+// tournamentMu is held.
+func (h *Hub) openTournamentTable(t *hostedTournament, g *tournament.Game, gameCode string) {
+	rules, _ := game.LookupRuleset(t.Ruleset)
+	a, _ := t.Pair(g.PairA)
+	b, _ := t.Pair(g.PairB)
+
+	lobby := newLobby(game.FourPlayers, 1, game.Settings{
+		TargetScore:  t.PointsGoal,
+		MortoRule:    game.MortoAside,
+		Rules:        rules,
+		Rounding:     game.RoundTruncate,
+		TrickPause:   h.config.TrickPause,
+		ReadyTimeout: h.config.ReadyTimeout,
+	})
+	lobby.SeatsLocked = true
+	if t.JoinTokens == nil {
+		t.JoinTokens = make(map[string][]string)
+	}
+	if len(t.JoinTokens[gameCode]) != game.FourPlayers {
+		t.JoinTokens[gameCode] = []string{uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	}
+	lobby.Reserved = t.JoinTokens[gameCode]
+
+	h.lobbyMu.Lock()
+	h.lobbies[gameCode] = lobby
+	h.lobbyMu.Unlock()
+
+	g.GameCode = gameCode
+	h.tournamentOf[gameCode] = t.ID
+	log.Printf("Tournament %s: table %s opened for %s vs %s.", t.ID, gameCode, a.Name, b.Name)
+}
+
+// RestoreTournaments reopens the tables of every game still to be played in
+// the tournaments that were running when the server stopped. Games that were
+// under way start over.
+func (h *Hub) RestoreTournaments() {
+	records, err := h.db.GetTournamentsByStatus(tournament.Running)
+	if err != nil {
+		log.Printf("Error loading running tournaments: %v", err)
+		return
+	}
+
+	h.tournamentMu.Lock()
+	defer h.tournamentMu.Unlock()
+	for _, record := range records {
+		t := &hostedTournament{}
+		if err := json.Unmarshal([]byte(record.Data), t); err != nil || t.Tournament == nil {
+			log.Printf("Error decoding tournament %s: %v", record.ID, err)
+			continue
+		}
+		h.tournaments[t.ID] = t
+		for _, g := range t.CurrentRound() {
+			if g.Winner == 0 {
+				h.openTournamentTable(t, g, g.GameCode)
+			}
+		}
+		h.advanceTournament(t)
+		log.Printf("Tournament %s (%s) restored at round %d.", t.ID, t.Name, len(t.Rounds))
+	}
+}
+
+// finishTournamentGame records the game a tournament table played, closes the
+// table and moves the tournament on. Returns false if the table isn't part of
+// a tournament.
+func (h *Hub) finishTournamentGame(gameCode string, outcome game.Outcome) bool {
+	h.tournamentMu.Lock()
+	tournamentID, ok := h.tournamentOf[gameCode]
+	if !ok {
+		h.tournamentMu.Unlock()
+		return false
+	}
+	delete(h.tournamentOf, gameCode)
+	t := h.tournaments[tournamentID]
+	if t.Record(gameCode, outcome.GameID, outcome.WinningTeam, h.storedScores(outcome), outcome.LeaverID != "") {
+		log.Printf("Tournament %s: game %s at table %s won by team %d.", t.ID, outcome.GameID, gameCode, outcome.WinningTeam)
+		h.advanceTournament(t)
+	}
+	h.tournamentMu.Unlock()
+
+	h.closeTable(gameCode, outcome.LeaverID)
+	return true
+}
+
+// storedScores returns the final team scores stored with a game's result, by
+// team number, falling back to the outcome's if the result can't be read.
+func (h *Hub) storedScores(outcome game.Outcome) []int {
+	result, err := h.db.GetByID(outcome.GameID)
+	if err != nil {
+		log.Printf("Error loading result of game %s: %v", outcome.GameID, err)
+		return outcome.Scores
+	}
+	scores := make([]int, len(result.Scores))
+	for _, s := range result.Scores {
+		if s.TeamNumber < 1 || s.TeamNumber > len(scores) {
+			return outcome.Scores
+		}
+		scores[s.TeamNumber-1] = s.Score
+	}
+	return scores
+}
+
+// settleTable records the organizer's result for a table of the current round
+// whose game isn't being played and moves the tournament on. Returns the game
+// code of the table, which the caller closes once tournamentMu is released.
+// The error explains why the table can't be settled.
+func (h *Hub) settleTable(t *hostedTournament, req RecordResultRequest) (string, error) {
+	if t.Status != tournament.Running {
+		return "", errors.New("The tournament is over")
+	}
+	games := t.CurrentRound()
+	if req.Table < 1 || req.Table > len(games) {
+		return "", errors.New("No such table in the current round")
+	}
+	g := games[req.Table-1]
+	if g.Winner != 0 {
+		return "", errors.New("The table already has a result")
+	}
+	winningTeam := 1
+	if req.Winner == g.PairB {
+		winningTeam = 2
+	} else if req.Winner != g.PairA {
+		return "", errors.New("The winner must be one of the table's pairs")
+	}
+	scores := []int{req.ScoreA, req.ScoreB}
+	if req.Walkover {
+		scores = nil
+	}
+
+	h.gameMu.Lock()
+	if _, playing := h.games[g.GameCode]; playing {
+		h.gameMu.Unlock()
+		return "", errors.New("The game at this table is being played")
+	}
+	h.lobbyMu.Lock()
+	delete(h.lobbies, g.GameCode)
+	h.lobbyMu.Unlock()
+	h.gameMu.Unlock()
+
+	delete(h.tournamentOf, g.GameCode)
+	t.Record(g.GameCode, "", winningTeam, scores, req.Walkover)
+	log.Printf("Tournament %s: table %s settled by the organizer, won by pair %d.", t.ID, g.GameCode, req.Winner)
+	h.advanceTournament(t)
+	return g.GameCode, nil
+}
+
+// findTournament returns a tournament by ID. Live tournaments are answered from
+// memory, older ones from the database. Assumes tournamentMu is held.
+func (h *Hub) findTournament(id string) (*hostedTournament, error) {
+	if t, ok := h.tournaments[id]; ok {
+		return t, nil
+	}
+	record, err := h.db.GetTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	t := &hostedTournament{}
+	if err := json.Unmarshal([]byte(record.Data), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// pairings lists the games of a round, counting from 1. The join tokens of
+// the seats are shown if given, by game code.
+func pairings(t *tournament.Tournament, round int, joinTokens map[string][]string) TournamentRound {
+	games := t.Rounds[round-1]
+	view := TournamentRound{TournamentID: t.ID, Round: round, Complete: true, Pairings: []TournamentPairing{}}
+	for i, g := range games {
+		a, _ := t.Pair(g.PairA)
+		pairing := TournamentPairing{
+			Table:    i + 1,
+			GameCode: g.GameCode,
+			PairA:    a,
+			Winner:   g.Winner,
+			ScoreA:   g.ScoreA,
+			ScoreB:   g.ScoreB,
+			GameID:   g.GameID,
+			Forfeit:  g.Forfeit,
+		}
+		if b, ok := t.Pair(g.PairB); ok {
+			pairing.PairB = &b
+			if tokens := joinTokens[g.GameCode]; len(tokens) == game.FourPlayers {
+				pairing.JoinTokens = map[string]string{
+					a.Players[0]: tokens[0],
+					b.Players[0]: tokens[1],
+					a.Players[1]: tokens[2],
+					b.Players[1]: tokens[3],
+				}
+			}
+		}
+		view.Complete = view.Complete && g.Winner != 0
+		view.Pairings = append(view.Pairings, pairing)
+	}
+	return view
+}
+
+// TournamentsHandler creates a tournament (POST) or lists them all (GET).
+func TournamentsHandler(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tournaments, err := hub.db.GetTournaments()
+		if err != nil {
+			http.Error(w, "Failed to fetch tournaments", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tournaments)
+
+	case http.MethodPost:
+		organizer, ok := organizerAccount(hub, w, r)
+		if !ok {
+			return
+		}
+		var req CreateTournamentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		t, err := hub.createTournament(req, organizer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hub.tournamentMu.Lock()
+		defer hub.tournamentMu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(t.Tournament)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetTournamentHandler returns a tournament with its pairs and every round so far.
+func GetTournamentHandler(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	hub.tournamentMu.Lock()
+	defer hub.tournamentMu.Unlock()
+	t, ok := lookupTournament(hub, w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t.Tournament)
+}
+
+// GetStandingsHandler returns the standings of a tournament, ties broken by
+// point difference.
+func GetStandingsHandler(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	hub.tournamentMu.Lock()
+	defer hub.tournamentMu.Unlock()
+	t, ok := lookupTournament(hub, w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t.Standings())
+}
+
+// GetPairingsHandler returns the pairings of the current round, or of the
+// round asked for with ?round=N, with the game code of each table. The
+// organizer also gets the join token of every seat.
+func GetPairingsHandler(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	hub.tournamentMu.Lock()
+	defer hub.tournamentMu.Unlock()
+	t, ok := lookupTournament(hub, w, r)
+	if !ok {
+		return
+	}
+
+	round := len(t.Rounds)
+	if roundParam := r.URL.Query().Get("round"); roundParam != "" {
+		n, err := strconv.Atoi(roundParam)
+		if err != nil || n < 1 || n > len(t.Rounds) {
+			http.Error(w, "Invalid round", http.StatusBadRequest)
+			return
+		}
+		round = n
+	}
+	if round == 0 {
+		http.Error(w, "No round has been paired", http.StatusNotFound)
+		return
+	}
+
+	var joinTokens map[string][]string
+	if account, ok := hub.authenticate(r); ok && account.ID != "" && account.ID == t.OrganizerID {
+		joinTokens = t.JoinTokens
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pairings(t.Tournament, round, joinTokens))
+}
+
+// RecordResultHandler lets the organizer record the result of a table of the
+// current round, or a walkover, when its game can't be played on the server.
+func RecordResultHandler(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	organizer, ok := organizerAccount(hub, w, r)
+	if !ok {
+		return
+	}
+	var req RecordResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	hub.tournamentMu.Lock()
+	t, ok := lookupTournament(hub, w, r)
+	if !ok {
+		hub.tournamentMu.Unlock()
+		return
+	}
+	if t.OrganizerID != organizer.ID {
+		hub.tournamentMu.Unlock()
+		http.Error(w, "Only the organizer can record results", http.StatusForbidden)
+		return
+	}
+	gameCode, err := hub.settleTable(t, req)
+	if err != nil {
+		hub.tournamentMu.Unlock()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, _ := json.Marshal(t.Tournament)
+	hub.tournamentMu.Unlock()
+
+	hub.closeTable(gameCode, "")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// organizerAccount returns the account a request to run a tournament logged in
+// with, answering the request with an error if it didn't.
+func organizerAccount(hub *Hub, w http.ResponseWriter, r *http.Request) (database.Account, bool) {
+	account, ok := hub.authenticate(r)
+	if !ok {
+		http.Error(w, "Invalid or expired login token", http.StatusUnauthorized)
+		return database.Account{}, false
+	}
+	if account.ID == "" {
+		http.Error(w, "Log in to run a tournament", http.StatusUnauthorized)
+		return database.Account{}, false
+	}
+	return account, true
+}
+
+// lookupTournament fetches the tournament named in the path, answering the
+// request with an error if it can't. Assumes tournamentMu is held.
+func lookupTournament(hub *Hub, w http.ResponseWriter, r *http.Request) (*hostedTournament, bool) {
+	t, err := hub.findTournament(r.PathValue("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Tournament not found", http.StatusNotFound)
+			return nil, false
+		}
+		log.Printf("Error loading tournament %s: %v", r.PathValue("id"), err)
+		http.Error(w, "Failed to fetch tournament", http.StatusInternalServerError)
+		return nil, false
+	}
+	return t, true
+}
@@ -44,4 +44,37 @@ var cardValues = map[string]int{
 	"11":	  1, // Scaled: 1/3 * 3
 	"12": 	  1, // Scaled: 1/3 * 3
 	"13":     1, // Scaled: 1/3 * 3
-}
\ No newline at end of file
+}
+// Briscola ranks the cards differently: the ace and the 3 are the strongest
+// and carry most of the 120 points in the deck.
+var briscolaOrder = map[string]int{
+	"1":  10,
+	"3":  9,
+	"13": 8, // Re
+	"12": 7, // Cavallo
+	"11": 6, // Fante
+	"7":  5,
+	"6":  4,
+	"5":  3,
+	"4":  2,
+	"2":  1,
+}
+
+// Briscola card values (not scaled)
+var briscolaValues = map[string]int{
+	"1":  11,
+	"3":  10,
+	"13": 4,
+	"12": 3,
+	"11": 2,
+}
+
+// BriscolaOrder returns the strength of the card within its suit in Briscola.
+func (c Card) BriscolaOrder() int {
+	return briscolaOrder[c.Rank]
+}
+
+// BriscolaPoints returns what the card is worth in Briscola.
+func (c Card) BriscolaPoints() int {
+	return briscolaValues[c.Rank]
+}
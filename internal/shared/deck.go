@@ -1,8 +1,12 @@
 package shared
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"log"
-	"math/rand/v2"
+	mathrand "math/rand/v2"
 )
 
 // Deck represents a collection of cards.
@@ -38,15 +42,55 @@ func NewDeck() *Deck {
 	return &Deck{Cards: cards}
 }
 
-// Shuffle randomizes the order of cards in the deck.
-func (d *Deck) Shuffle() {
-	rand.Shuffle(len(d.Cards), func(i, j int) {
+// Seed drives a shuffle. It is written as hex in JSON.
+type Seed [32]byte
+
+// NewSeed draws a seed from the operating system's cryptographic random source.
+func NewSeed() (Seed, error) {
+	var seed Seed
+	_, err := rand.Read(seed[:])
+	return seed, err
+}
+
+// Commitment is the hex SHA-256 hash of the seed. Publishing it before the deal
+// and the seed afterwards lets anyone check that the deal wasn't changed.
+func (s Seed) Commitment() string {
+	sum := sha256.Sum256(s[:])
+	return hex.EncodeToString(sum[:])
+}
+
+func (s Seed) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+func (s Seed) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Seed) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(s) {
+		return fmt.Errorf("seed must be %d bytes, got %d", len(s), len(b))
+	}
+	copy(s[:], b)
+	return nil
+}
+
+// Shuffle puts the deck in an order fully determined by seed, so the same seed
+// always produces the same deal.
+func (d *Deck) Shuffle(seed Seed) {
+	rng := mathrand.New(mathrand.NewChaCha8(seed))
+	rng.Shuffle(len(d.Cards), func(i, j int) {
 		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
 	})
-	log.Println("Deck shuffled.")
+	log.Printf("Deck shuffled (commitment %s).", seed.Commitment())
 }
 
 // Deal distributes cards to players. Returns nil if not enough cards.
+// Cards that are not dealt stay in the deck as the stock.
 func (d *Deck) Deal(numPlayers, cardsPerPlayer int) [][]Card {
 	totalCardsNeeded := numPlayers * cardsPerPlayer
 	if len(d.Cards) < totalCardsNeeded {
@@ -65,8 +109,28 @@ func (d *Deck) Deal(numPlayers, cardsPerPlayer int) [][]Card {
 		start = end
 	}
 
-	d.Cards = []Card{}
-	log.Printf("Dealt %d cards to %d players.", cardsPerPlayer, numPlayers)
+	d.Cards = d.Cards[totalCardsNeeded:]
+	log.Printf("Dealt %d cards to %d players, %d left in stock.", cardsPerPlayer, numPlayers, len(d.Cards))
 	return dealt
 }
 
+// Draw takes the top card of the deck. Returns false if the deck is empty.
+func (d *Deck) Draw() (Card, bool) {
+	if len(d.Cards) == 0 {
+		return Card{}, false
+	}
+	card := d.Cards[0]
+	d.Cards = d.Cards[1:]
+	return card, true
+}
+
+// TurnUp shows the top card of the deck and puts it at the bottom, where it
+// will be the last card drawn (the Briscola trump card).
+func (d *Deck) TurnUp() (Card, bool) {
+	if len(d.Cards) == 0 {
+		return Card{}, false
+	}
+	card := d.Cards[0]
+	d.Cards = append(d.Cards[1:], card)
+	return card, true
+}
@@ -0,0 +1,113 @@
+package shared
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// countingSeed returns the seed 00 01 02 ... 1f.
+func countingSeed() Seed {
+	var seed Seed
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	return seed
+}
+
+func TestShuffle(t *testing.T) {
+	tests := []struct {
+		name       string
+		seed       Seed
+		commitment string
+		top        []Card // First cards of the shuffled deck, empty to skip the check
+	}{
+		{
+			name:       "counting seed",
+			seed:       countingSeed(),
+			commitment: "630dcd2966c4336691125448bbb25b4ff412a49c732db2c8abc1b8581bd710dd",
+			top: []Card{
+				{Suit: Spade, Rank: "13"},
+				{Suit: Kope, Rank: "12"},
+				{Suit: Denari, Rank: "13"},
+				{Suit: Bastoni, Rank: "6"},
+				{Suit: Denari, Rank: "7"},
+			},
+		},
+		{
+			name:       "zero seed",
+			seed:       Seed{},
+			commitment: "66687aadf862bd776c8fc18b8e9f8e20089714856ee233b3902a591d0d5f2925",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := NewDeck(), NewDeck()
+			first.Shuffle(tt.seed)
+			second.Shuffle(tt.seed)
+			if !reflect.DeepEqual(first.Cards, second.Cards) {
+				t.Fatalf("the same seed shuffled two different orders")
+			}
+			if reflect.DeepEqual(first.Cards, NewDeck().Cards) {
+				t.Errorf("Shuffle() left the deck in order")
+			}
+			if !sameCards(first.Cards, NewDeck().Cards) {
+				t.Errorf("Shuffle() changed the cards in the deck")
+			}
+			for i, want := range tt.top {
+				if got := first.Cards[i]; got.Suit != want.Suit || got.Rank != want.Rank {
+					t.Errorf("card %d = %s %s, want %s %s", i, got.Rank, got.Suit, want.Rank, want.Suit)
+				}
+			}
+
+			if got := tt.seed.Commitment(); got != tt.commitment {
+				t.Errorf("Commitment() = %s, want %s", got, tt.commitment)
+			}
+			// Anyone given the revealed seed can check it against the published commitment
+			var revealed Seed
+			if err := revealed.UnmarshalText([]byte(tt.seed.String())); err != nil {
+				t.Fatalf("UnmarshalText(): %v", err)
+			}
+			sum := sha256.Sum256(revealed[:])
+			if hex.EncodeToString(sum[:]) != tt.commitment {
+				t.Errorf("the revealed seed does not match the commitment")
+			}
+			replayed := NewDeck()
+			replayed.Shuffle(revealed)
+			if !reflect.DeepEqual(replayed.Cards, first.Cards) {
+				t.Errorf("the revealed seed does not reproduce the deal")
+			}
+		})
+	}
+}
+
+func TestShuffleDifferentSeeds(t *testing.T) {
+	other := countingSeed()
+	other[0] ^= 1
+	first, second := NewDeck(), NewDeck()
+	first.Shuffle(countingSeed())
+	second.Shuffle(other)
+	if reflect.DeepEqual(first.Cards, second.Cards) {
+		t.Errorf("different seeds shuffled the same order")
+	}
+}
+
+// sameCards reports whether a and b hold the same cards in any order.
+func sameCards(a, b []Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := map[Card]int{}
+	for _, c := range a {
+		count[c]++
+	}
+	for _, c := range b {
+		count[c]--
+		if count[c] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"fmt"
 	"log"
 )
 
@@ -23,6 +24,7 @@ type Player struct {
 	Hand         []Card        // Cards currently held by the player
 	DesiredTeam  TeamEnum      // Desired team for the player
 	Declarations []Declaration // Declarations made by the player
+	AccountID    string        // Registered account playing this seat, empty for a guest
 }
 
 // NewPlayer creates a new player with the given ID and name.
@@ -70,31 +72,42 @@ func (p *Player) HasSuit(suit Suit) bool {
 	return false // Player does not have the suit
 }
 
+// AddDeclaration records a declaration if the hand backs it up. Declarations of
+// an unknown type are rejected like any other invalid one.
 func (p *Player) AddDeclaration(declaration Declaration) DeclarationResult {
+	result, reason := CheckDeclaration(p.Hand, p.Declarations, declaration)
+	if !result.Success {
+		log.Print(reason)
+		return result
+	}
+	p.Declarations = append(p.Declarations, declaration)
+	return result
+}
+
+// CheckDeclaration reports what a declaration would score for the given hand
+// without recording it. made lists the declarations already made this round.
+// On failure the second return value explains why.
+func CheckDeclaration(hand []Card, made []Declaration, declaration Declaration) (DeclarationResult, string) {
 	switch declaration.Type {
 	case "napola":
 		num_of_cards := 0
-		for _, c := range p.Hand {
+		for _, c := range hand {
 			if c.Suit == declaration.Suit && (c.Rank == "1" || c.Rank == "2" || c.Rank == "3") {
 				num_of_cards++
 			}
 		}
 		if num_of_cards != 3 {
-			log.Printf("Invalid napola declaration: %d cards of suit %s found, expected 3.", num_of_cards, declaration.Suit)
-			return DeclarationResult{Success: false, Points: 0}
+			return DeclarationResult{Success: false, Points: 0}, fmt.Sprintf("Invalid napola declaration: %d cards of suit %s found, expected 3.", num_of_cards, declaration.Suit)
 		}
-		for _, d := range p.Declarations {
+		for _, d := range made {
 			if d.Type == "napola" && d.Suit == declaration.Suit {
-				log.Printf("Invalid napola declaration: already declared for suit %s.", declaration.Suit)
-				return DeclarationResult{Success: false, Points: 0}
+				return DeclarationResult{Success: false, Points: 0}, fmt.Sprintf("Invalid napola declaration: already declared for suit %s.", declaration.Suit)
 			}
 		}
-		p.Declarations = append(p.Declarations, declaration)
-		return DeclarationResult{Success: true, Points: num_of_cards} // Points for napola
+		return DeclarationResult{Success: true, Points: num_of_cards}, "" // Points for napola
 	case "three_or_four_of_kind":
 		if declaration.Rank != "1" && declaration.Rank != "2" && declaration.Rank != "3" {
-			log.Printf("Invalid three_or_four_of_kind declaration: rank %s is not valid.", declaration.Rank)
-			return DeclarationResult{Success: false, Points: 0}
+			return DeclarationResult{Success: false, Points: 0}, fmt.Sprintf("Invalid three_or_four_of_kind declaration: rank %s is not valid.", declaration.Rank)
 		}
 		num_of_cards := 0
 		suits := map[Suit]bool{
@@ -104,20 +117,18 @@ func (p *Player) AddDeclaration(declaration Declaration) DeclarationResult {
 			Kope:    false,
 		}
 
-		for _, c := range p.Hand {
+		for _, c := range hand {
 			if c.Rank == declaration.Rank {
 				num_of_cards++
 				suits[c.Suit] = true
 			}
 		}
 		if num_of_cards != 3 && num_of_cards != 4 {
-			log.Printf("Invalid three_or_four_of_kind declaration: %d cards of rank %s found, expected 3 or 4.", num_of_cards, declaration.Rank)
-			return DeclarationResult{Success: false, Points: 0}
+			return DeclarationResult{Success: false, Points: 0}, fmt.Sprintf("Invalid three_or_four_of_kind declaration: %d cards of rank %s found, expected 3 or 4.", num_of_cards, declaration.Rank)
 		}
-		for _, d := range p.Declarations {
+		for _, d := range made {
 			if d.Type == "three_or_four_of_kind" && d.Rank == declaration.Rank {
-				log.Printf("Invalid three_or_four_of_kind declaration: already declared for rank %s.", declaration.Rank)
-				return DeclarationResult{Success: false, Points: 0}
+				return DeclarationResult{Success: false, Points: 0}, fmt.Sprintf("Invalid three_or_four_of_kind declaration: already declared for rank %s.", declaration.Rank)
 			}
 		}
 		var without_suit Suit
@@ -131,10 +142,28 @@ func (p *Player) AddDeclaration(declaration Declaration) DeclarationResult {
 			}
 		}
 
-		p.Declarations = append(p.Declarations, declaration)
-		return DeclarationResult{Success: true, Points: num_of_cards, WithoutSuit: without_suit} // Points for three_or_four_of_kind
+		return DeclarationResult{Success: true, Points: num_of_cards, WithoutSuit: without_suit}, "" // Points for three_or_four_of_kind
 	default:
-		log.Panicf("Invalid declaration type: %s", declaration.Type)
-		return DeclarationResult{Success: false, Points: 0} // Invalid declaration
+		return DeclarationResult{Success: false, Points: 0}, fmt.Sprintf("Invalid declaration type: %s", declaration.Type)
+	}
+}
+
+// AvailableDeclarations lists every declaration the hand can still make, given
+// the declarations already made this round. It does not modify anything.
+func AvailableDeclarations(hand []Card, made []Declaration) []Declaration {
+	candidates := []Declaration{}
+	for _, suit := range []Suit{Denari, Spade, Bastoni, Kope} {
+		candidates = append(candidates, Declaration{Type: "napola", Suit: suit})
+	}
+	for _, rank := range []string{"1", "2", "3"} {
+		candidates = append(candidates, Declaration{Type: "three_or_four_of_kind", Rank: rank})
+	}
+
+	available := []Declaration{}
+	for _, d := range candidates {
+		if result, _ := CheckDeclaration(hand, made, d); result.Success {
+			available = append(available, d)
+		}
 	}
+	return available
 }
@@ -14,19 +14,19 @@ const (
 
 // Team represents a team in the Tressette game.
 type Team struct {
-	ID         string     `json:"id"`
-	Players    [2]*Player `json:"players"`
-	Score      int        `json:"score"`
-	TotalScore int        `json:"total_score"`
-	TeamNumber int        `json:"team_number"` // Logical team number (1 or 2)
+	ID         string    `json:"id"`
+	Players    []*Player `json:"players"`
+	Score      int       `json:"score"`
+	TotalScore int       `json:"total_score"`
+	TeamNumber int       `json:"team_number"` // Logical team number (1 or 2)
 }
 
 // NewTeam creates a new team with the given logical number and players.
 // It generates a unique UUID for the team ID.
-func NewTeam(teamNumber int, player1, player2 *Player) *Team {
+func NewTeam(teamNumber int, players ...*Player) *Team {
 	return &Team{
 		ID:         uuid.NewString(), // Generate UUID
-		Players:    [2]*Player{player1, player2},
+		Players:    players,
 		Score:      0,
 		TotalScore: 0,
 		TeamNumber: teamNumber, // Store the logical team number
@@ -50,4 +50,3 @@ func (t *Team) TransferScore() {
 	t.TotalScore += s
 	t.ResetScore()
 }
-
@@ -27,6 +27,29 @@ func (t *Trick) AddCard(card Card, playerIndex int) {
 	t.Cards = append(t.Cards, PlayedCard{Card: card, PlayerIndex: playerIndex})
 }
 
+// DetermineWinnerWithTrump determines the winner of a trick played with a trump
+// suit: the strongest trump wins, otherwise the strongest card of the led suit.
+// strength ranks cards within a suit, since games order the ranks differently.
+func (t *Trick) DetermineWinnerWithTrump(trump Suit, strength func(Card) int) PlayedCard {
+	if len(t.Cards) == 0 {
+		log.Panicf("Error: Cannot determine winner of an empty trick.")
+	}
+
+	winner := t.Cards[0]
+	for _, playedCard := range t.Cards[1:] {
+		card := playedCard.Card
+		switch {
+		case card.Suit == winner.Card.Suit && strength(card) > strength(winner.Card):
+			winner = playedCard
+		case card.Suit == trump && winner.Card.Suit != trump:
+			winner = playedCard
+		}
+	}
+
+	t.WinnerIndex = winner.PlayerIndex
+	return winner
+}
+
 // DetermineWinner determines the winner of the trick based on Tressette rules.
 // Requires the suit that was led for the trick.
 func (t *Trick) DetermineWinner(ledSuit Suit) PlayedCard {
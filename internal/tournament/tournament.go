@@ -0,0 +1,421 @@
+// Package tournament runs club tournaments between fixed pairs: it pairs the
+// teams for each round, records the results and decides when the tournament
+// is over. It knows nothing about tables; the server opens one per game.
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type Format string
+
+const (
+	SingleElimination Format = "single_elimination" // Out after one loss
+	DoubleElimination Format = "double_elimination" // Out after two losses
+	Swiss             Format = "swiss"              // Everyone plays every round; the table decides
+)
+
+const (
+	MinPairs = 2
+	MaxPairs = 64
+)
+
+// Status of a tournament.
+const (
+	Running  = "running"
+	Finished = "finished"
+)
+
+// Pair is a team of two registered for the whole tournament.
+type Pair struct {
+	ID      int       `json:"id"` // Seed, starting at 1 in registration order
+	Name    string    `json:"name"`
+	Players [2]string `json:"players"` // Names of the players, who sit opposite each other
+}
+
+// Game is one pairing of a round. PairA plays as team 1, from seats 0 and 2.
+type Game struct {
+	PairA    int    `json:"pair_a"`
+	PairB    int    `json:"pair_b"` // 0 for a bye, which PairA wins without playing
+	GameCode string `json:"game_code,omitempty"`
+	GameID   string `json:"game_id,omitempty"` // Stored result, empty for a bye
+	Winner   int    `json:"winner"`            // Pair ID of the winner, 0 until the game is over
+	ScoreA   int    `json:"score_a"`
+	ScoreB   int    `json:"score_b"`
+	Forfeit  bool   `json:"forfeit,omitempty"` // Ended by a player leaving
+}
+
+// Bye reports whether the game is a bye.
+func (g *Game) Bye() bool {
+	return g.PairB == 0
+}
+
+// Tournament is the state of a tournament: its pairs and every round so far.
+type Tournament struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Format      Format    `json:"format"`
+	SwissRounds int       `json:"swiss_rounds,omitempty"` // Rounds a Swiss tournament lasts
+	PointsGoal  int       `json:"points_goal"`
+	Ruleset     string    `json:"ruleset"`
+	Pairs       []Pair    `json:"pairs"`
+	Rounds      [][]*Game `json:"rounds"`
+	Status      string    `json:"status"`
+	Winner      int       `json:"winner"` // Pair ID of the winner, 0 until finished
+	CreatedAt   string    `json:"created_at"`
+}
+
+// Standing is a pair's record so far.
+type Standing struct {
+	Rank          int    `json:"rank"`
+	PairID        int    `json:"pair_id"`
+	Name          string `json:"name"`
+	Played        int    `json:"played"` // Games played, byes not included
+	Wins          int    `json:"wins"`   // Byes included
+	Losses        int    `json:"losses"`
+	PointsFor     int    `json:"points_for"`
+	PointsAgainst int    `json:"points_against"`
+	PointDiff     int    `json:"point_diff"`
+	Eliminated    bool   `json:"eliminated,omitempty"`
+}
+
+// New sets up a tournament. For Swiss, swissRounds of zero picks enough rounds
+// to find a single unbeaten pair.
+func New(id, name string, format Format, pairs []Pair, swissRounds int) (*Tournament, error) {
+	if format != SingleElimination && format != DoubleElimination && format != Swiss {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if len(pairs) < MinPairs || len(pairs) > MaxPairs {
+		return nil, fmt.Errorf("a tournament needs %d to %d pairs", MinPairs, MaxPairs)
+	}
+	names := make(map[string]bool)
+	for i := range pairs {
+		pairs[i].ID = i + 1
+		if pairs[i].Name == "" {
+			pairs[i].Name = pairs[i].Players[0] + " & " + pairs[i].Players[1]
+		}
+		for _, player := range pairs[i].Players {
+			if player == "" {
+				return nil, errors.New("every pair needs two players")
+			}
+			if names[player] {
+				return nil, fmt.Errorf("%s is registered twice", player)
+			}
+			names[player] = true
+		}
+	}
+	if format == Swiss {
+		if swissRounds == 0 {
+			swissRounds = int(math.Ceil(math.Log2(float64(len(pairs)))))
+		}
+		if swissRounds < 1 || swissRounds >= len(pairs)+len(pairs)%2 {
+			return nil, errors.New("invalid number of Swiss rounds")
+		}
+	}
+
+	return &Tournament{
+		ID:          id,
+		Name:        name,
+		Format:      format,
+		SwissRounds: swissRounds,
+		Pairs:       pairs,
+		Rounds:      [][]*Game{},
+		Status:      Running,
+	}, nil
+}
+
+// CurrentRound returns the games of the round being played, nil before the first.
+func (t *Tournament) CurrentRound() []*Game {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return t.Rounds[len(t.Rounds)-1]
+}
+
+// RoundComplete reports whether every game of the current round has a winner.
+func (t *Tournament) RoundComplete() bool {
+	for _, g := range t.CurrentRound() {
+		if g.Winner == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Record stores the result of the game played at a table. winningTeam is 1 for
+// PairA and 2 for PairB, scores the final totals by team number. Returns false
+// if no game waits for a result at that table.
+func (t *Tournament) Record(gameCode, gameID string, winningTeam int, scores []int, forfeit bool) bool {
+	for _, g := range t.CurrentRound() {
+		if g.GameCode != gameCode || g.Winner != 0 {
+			continue
+		}
+		g.GameID = gameID
+		g.Forfeit = forfeit
+		if len(scores) >= 2 {
+			g.ScoreA, g.ScoreB = scores[0], scores[1]
+		}
+		g.Winner = g.PairA
+		if winningTeam == 2 {
+			g.Winner = g.PairB
+		}
+		return true
+	}
+	return false
+}
+
+// NextRound pairs the next round once the current one is complete. Byes are
+// decided at once. Returns nil and marks the tournament finished when it is over.
+func (t *Tournament) NextRound() []*Game {
+	if t.Status == Finished || !t.RoundComplete() {
+		return nil
+	}
+	if winner := t.decided(); winner != 0 {
+		t.Status = Finished
+		t.Winner = winner
+		return nil
+	}
+
+	var games []*Game
+	switch t.Format {
+	case SingleElimination:
+		games = t.pairEliminated(1)
+	case DoubleElimination:
+		games = t.pairEliminated(2)
+	case Swiss:
+		games = t.pairSwiss()
+	}
+	for _, g := range games {
+		if g.Bye() {
+			g.Winner = g.PairA
+		}
+	}
+	t.Rounds = append(t.Rounds, games)
+	return games
+}
+
+// decided returns the winner once the tournament is over, or 0.
+func (t *Tournament) decided() int {
+	if t.Format == Swiss {
+		if len(t.Rounds) < t.SwissRounds {
+			return 0
+		}
+		return t.Standings()[0].PairID
+	}
+	alive := t.alive(t.maxLosses())
+	if len(alive) > 1 {
+		return 0
+	}
+	return alive[0]
+}
+
+// maxLosses is how many losses put a pair out of an elimination tournament.
+func (t *Tournament) maxLosses() int {
+	if t.Format == DoubleElimination {
+		return 2
+	}
+	return 1
+}
+
+// alive lists the pairs with fewer than maxLosses losses, by seed.
+func (t *Tournament) alive(maxLosses int) []int {
+	records := t.records()
+	var ids []int
+	for _, p := range t.Pairs {
+		if records[p.ID].Losses < maxLosses {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
+
+// pairEliminated pairs the pairs still in an elimination tournament. Pairs
+// meet others with as many losses, the best seed against the worst; in a
+// round where the numbers don't work out, the best seeds that haven't had a
+// bye yet get one.
+func (t *Tournament) pairEliminated(maxLosses int) []*Game {
+	records := t.records()
+	groups := make([][]int, maxLosses)
+	for _, id := range t.alive(maxLosses) {
+		losses := records[id].Losses
+		groups[losses] = append(groups[losses], id)
+	}
+
+	var games []*Game
+	var leftovers []int
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		byes := 0
+		if len(group)%2 == 1 {
+			byes = 1
+		}
+		if maxLosses == 1 {
+			// Single elimination: byes bring the field down to a power of two
+			size := 1
+			for size < len(group) {
+				size *= 2
+			}
+			byes = size - len(group)
+		}
+		sort.SliceStable(group, func(i, j int) bool { return !records[group[i]].hadBye && records[group[j]].hadBye })
+		leftovers = append(leftovers, group[:byes]...)
+		rest := append([]int{}, group[byes:]...)
+		sort.Ints(rest)
+		games = append(games, pairOutside(rest)...)
+	}
+
+	// An odd pair from each loss group play each other; a lone one waits a round
+	for len(leftovers) >= 2 && maxLosses > 1 {
+		games = append(games, &Game{PairA: leftovers[0], PairB: leftovers[1]})
+		leftovers = leftovers[2:]
+	}
+	for _, id := range leftovers {
+		games = append(games, &Game{PairA: id})
+	}
+	return games
+}
+
+// pairOutside pairs a list from the outside in: first against last and so on.
+func pairOutside(ids []int) []*Game {
+	var games []*Game
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		games = append(games, &Game{PairA: ids[i], PairB: ids[j]})
+	}
+	return games
+}
+
+// pairSwiss pairs every pair with the next one in the standings it hasn't
+// played yet. With an odd number of pairs, the lowest placed pair that hasn't
+// had a bye sits the round out.
+func (t *Tournament) pairSwiss() []*Game {
+	records := t.records()
+	var order []int
+	for _, s := range t.Standings() {
+		order = append(order, s.PairID)
+	}
+
+	var games []*Game
+	if len(order)%2 == 1 {
+		bye := len(order) - 1
+		for i := len(order) - 1; i >= 0; i-- {
+			if !records[order[i]].hadBye {
+				bye = i
+				break
+			}
+		}
+		games = append(games, &Game{PairA: order[bye]})
+		order = append(order[:bye:bye], order[bye+1:]...)
+	}
+
+	paired := make(map[int]bool)
+	for i, a := range order {
+		if paired[a] {
+			continue
+		}
+		opponent := 0
+		for _, b := range order[i+1:] {
+			if paired[b] {
+				continue
+			}
+			if opponent == 0 {
+				opponent = b // Fall back to a rematch if there is no one new left
+			}
+			if !records[a].opponents[b] {
+				opponent = b
+				break
+			}
+		}
+		paired[a], paired[opponent] = true, true
+		games = append(games, &Game{PairA: a, PairB: opponent})
+	}
+	return games
+}
+
+// record is a pair's tally over the games played so far.
+type record struct {
+	Standing
+	opponents map[int]bool
+	hadBye    bool
+}
+
+// records tallies every pair's games, by pair ID.
+func (t *Tournament) records() map[int]*record {
+	records := make(map[int]*record, len(t.Pairs))
+	for _, p := range t.Pairs {
+		records[p.ID] = &record{Standing: Standing{PairID: p.ID, Name: p.Name}, opponents: make(map[int]bool)}
+	}
+	for _, round := range t.Rounds {
+		for _, g := range round {
+			if g.Winner == 0 {
+				continue
+			}
+			a := records[g.PairA]
+			if g.Bye() {
+				a.Wins++
+				a.hadBye = true
+				continue
+			}
+			b := records[g.PairB]
+			a.opponents[g.PairB], b.opponents[g.PairA] = true, true
+			a.Played++
+			b.Played++
+			a.PointsFor += g.ScoreA
+			a.PointsAgainst += g.ScoreB
+			b.PointsFor += g.ScoreB
+			b.PointsAgainst += g.ScoreA
+			winner, loser := a, b
+			if g.Winner == g.PairB {
+				winner, loser = b, a
+			}
+			winner.Wins++
+			loser.Losses++
+		}
+	}
+	return records
+}
+
+// Standings ranks the pairs: pairs still in an elimination tournament first,
+// then by wins, then by point difference over their games, then by seed.
+func (t *Tournament) Standings() []Standing {
+	records := t.records()
+	standings := make([]Standing, 0, len(t.Pairs))
+	for _, p := range t.Pairs {
+		s := records[p.ID].Standing
+		s.PointDiff = s.PointsFor - s.PointsAgainst
+		s.Eliminated = t.Format != Swiss && s.Losses >= t.maxLosses()
+		standings = append(standings, s)
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if t.Winner != 0 && (a.PairID == t.Winner) != (b.PairID == t.Winner) {
+			return a.PairID == t.Winner
+		}
+		if a.Eliminated != b.Eliminated {
+			return !a.Eliminated
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.PointDiff != b.PointDiff {
+			return a.PointDiff > b.PointDiff
+		}
+		return a.PairID < b.PairID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// Pair returns the pair with the given ID.
+func (t *Tournament) Pair(id int) (Pair, bool) {
+	if id < 1 || id > len(t.Pairs) {
+		return Pair{}, false
+	}
+	return t.Pairs[id-1], true
+}
@@ -0,0 +1,209 @@
+package tournament
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// result is a finished game: the winning pair and both final scores.
+type result struct {
+	winner, scoreFor, scoreAgainst int
+}
+
+func newTournament(t *testing.T, format Format, pairs, swissRounds int) *Tournament {
+	t.Helper()
+	registered := make([]Pair, pairs)
+	for i := range registered {
+		registered[i].Players = [2]string{fmt.Sprintf("north%d", i), fmt.Sprintf("south%d", i)}
+	}
+	tr, err := New("t1", "Test cup", format, registered, swissRounds)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	return tr
+}
+
+// play pairs the next round and records its results.
+func play(t *testing.T, tr *Tournament, results []result) {
+	t.Helper()
+	if tr.NextRound() == nil {
+		t.Fatalf("NextRound() = nil before round %d", len(tr.Rounds)+1)
+	}
+	for i, g := range tr.CurrentRound() {
+		g.GameCode = fmt.Sprintf("R%dT%d", len(tr.Rounds), i+1)
+	}
+	for _, r := range results {
+		recorded := false
+		for _, g := range tr.CurrentRound() {
+			if g.Bye() || (g.PairA != r.winner && g.PairB != r.winner) {
+				continue
+			}
+			team, scores := 1, []int{r.scoreFor, r.scoreAgainst}
+			if g.PairB == r.winner {
+				team, scores = 2, []int{r.scoreAgainst, r.scoreFor}
+			}
+			recorded = tr.Record(g.GameCode, "", team, scores, false)
+		}
+		if !recorded {
+			t.Fatalf("pair %d has no game to win in round %d", r.winner, len(tr.Rounds))
+		}
+	}
+}
+
+// matchups lists the pair IDs of every game, PairB 0 for a bye.
+func matchups(games []*Game) [][2]int {
+	ids := [][2]int{}
+	for _, g := range games {
+		ids = append(ids, [2]int{g.PairA, g.PairB})
+	}
+	return ids
+}
+
+func TestNextRound(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		pairs       int
+		swissRounds int
+		rounds      [][]result // Results of the rounds played before the one checked
+		want        [][2]int
+	}{
+		{
+			name:   "single elimination byes fill the bracket for the best seeds",
+			format: SingleElimination,
+			pairs:  5,
+			want:   [][2]int{{4, 5}, {1, 0}, {2, 0}, {3, 0}},
+		},
+		{
+			name:   "single elimination pairs the winners",
+			format: SingleElimination,
+			pairs:  4,
+			rounds: [][]result{{{1, 11, 4}, {3, 11, 9}}},
+			want:   [][2]int{{1, 3}},
+		},
+		{
+			name:   "double elimination splits pairs by losses",
+			format: DoubleElimination,
+			pairs:  4,
+			rounds: [][]result{{{1, 11, 4}, {2, 11, 9}}},
+			want:   [][2]int{{1, 2}, {3, 4}},
+		},
+		{
+			name:   "double elimination gives a lone unbeaten pair a bye",
+			format: DoubleElimination,
+			pairs:  4,
+			rounds: [][]result{{{1, 11, 4}, {2, 11, 9}}, {{1, 11, 3}, {3, 11, 8}}},
+			want:   [][2]int{{2, 3}, {1, 0}},
+		},
+		{
+			name:   "double elimination pairs the odd pairs of two loss groups",
+			format: DoubleElimination,
+			pairs:  6,
+			rounds: [][]result{{{1, 11, 0}, {2, 11, 0}, {3, 11, 0}}},
+			want:   [][2]int{{2, 3}, {5, 6}, {1, 4}},
+		},
+		{
+			name:   "swiss gives the lowest placed pair the bye",
+			format: Swiss,
+			pairs:  3,
+			want:   [][2]int{{3, 0}, {1, 2}},
+		},
+		{
+			name:   "swiss moves the bye to a pair that hasn't had one",
+			format: Swiss,
+			pairs:  3,
+			rounds: [][]result{{{2, 11, 6}}},
+			want:   [][2]int{{1, 0}, {2, 3}},
+		},
+		{
+			name:        "swiss avoids rematches",
+			format:      Swiss,
+			pairs:       4,
+			swissRounds: 3,
+			rounds:      [][]result{{{1, 11, 5}, {3, 11, 10}}, {{1, 11, 9}, {2, 11, 2}}},
+			want:        [][2]int{{1, 4}, {2, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTournament(t, tt.format, tt.pairs, tt.swissRounds)
+			for _, results := range tt.rounds {
+				play(t, tr, results)
+			}
+			games := tr.NextRound()
+			if got := matchups(games); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NextRound() = %v, want %v", got, tt.want)
+			}
+			for _, g := range games {
+				if g.Bye() && g.Winner != g.PairA {
+					t.Errorf("bye of pair %d has winner %d", g.PairA, g.Winner)
+				}
+			}
+		})
+	}
+}
+
+func TestStandings(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		pairs  int
+		rounds [][]result
+		want   []int // Pair IDs from first to last
+	}{
+		{
+			name:   "point difference breaks ties on wins",
+			format: Swiss,
+			pairs:  4,
+			rounds: [][]result{{{1, 11, 5}, {3, 11, 10}}},
+			want:   []int{1, 3, 4, 2},
+		},
+		{
+			name:   "wins first, then point difference over every round",
+			format: Swiss,
+			pairs:  4,
+			rounds: [][]result{{{2, 11, 10}, {4, 11, 3}}, {{2, 11, 9}, {1, 11, 10}}},
+			want:   []int{2, 4, 1, 3},
+		},
+		{
+			name:   "seed breaks ties on point difference",
+			format: Swiss,
+			pairs:  4,
+			rounds: [][]result{{{2, 11, 7}, {4, 11, 7}}},
+			want:   []int{2, 4, 1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTournament(t, tt.format, tt.pairs, 0)
+			for _, results := range tt.rounds {
+				play(t, tr, results)
+			}
+			got := []int{}
+			for _, s := range tr.Standings() {
+				got = append(got, s.PairID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Standings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTournamentFinishes(t *testing.T) {
+	tr := newTournament(t, SingleElimination, 3, 0)
+	play(t, tr, []result{{3, 11, 6}})
+	play(t, tr, []result{{3, 11, 8}})
+	if games := tr.NextRound(); games != nil {
+		t.Fatalf("NextRound() = %v after the final, want nil", matchups(games))
+	}
+	if tr.Status != Finished || tr.Winner != 3 {
+		t.Errorf("status %s, winner %d; want %s, winner 3", tr.Status, tr.Winner, Finished)
+	}
+	if first := tr.Standings()[0]; first.PairID != 3 {
+		t.Errorf("Standings() ranks pair %d first, want the winner", first.PairID)
+	}
+}
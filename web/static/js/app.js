@@ -11,43 +11,28 @@ let roundOver = false // Flag to indicate if the round has ended
 let roundOverPayload = null // Store the payload for round over
 let gameOver = false // Flag to indicate if the game is over
 let canDeclare = false // Flag to indicate if the player can declare
-
-let declarations = [{
-    type: "napola",
-    suit: "Bastoni",
-    rank: "",
-    image: "images/cards/Bastoni_1.png",
-}, {
-    type: "napola",
-    suit: "Kope",
-    rank: "",
-    image: "images/cards/Kope_1.png",
-}, {
-    type: "napola",
-    suit: "Denari",
-    rank: "",
-    image: "images/cards/Denari_1.png",
-}, {
-    type: "napola",
-    suit: "Spade",
-    rank: "",
-    image: "images/cards/Spade_1.png",
-}, {
-    type: "three_or_four_of_kind",
-    suit: "",
-    rank: "1",
-    image: "images/cards/Bastoni_1.png",
-}, {
-    type: "three_or_four_of_kind",
-    suit: "",
-    rank: "2",
-    image: "images/cards/Bastoni_2.png",
-}, {
-    type: "three_or_four_of_kind",
-    suit: "",
-    rank: "3",
-    image: "images/cards/Bastoni_3.png",
-}]
+let declarationInfoTimer = null // Clears the announced declarations
+let rejoining = false // Flag to indicate a rejoin_game request is pending
+let stockRemaining = 0 // Cards left in the stock (heads-up games only)
+let gameMode = "classic" // "classic", "a_perdere" or "a_chiamare"
+let declarationsAllowed = true // Whether the ruleset scores declarations
+let eliminatedPlayers = [] // Players knocked out of an "a perdere" game
+let partnerId = null // "A chiamare": this round's partner of the caller, once known
+let isHost = false // Whether we host the lobby, and so may mute others in chat
+let seatsLocked = false // Whether the lobby host froze the seating
+let mutedPlayers = [] // Players the host has muted
+let chatNames = {} // Player ID -> name of everyone seen in chat
+let signalsAllowed = true // Whether the table allows busso, striscio and volo
+let selectedSignal = "" // Signal to send with the next card we lead
+let spectating = false // Watching a game without a seat; the table is shown from the first seat
+let shuffleCommitment = null // Hash of this round's shuffle seed, published before the deal
+let turnTimerInterval = null // Counts down the current player's clock
+
+const sessionTokenKey = "tressette-session-token" // localStorage key for the reconnect token
+const loginTokenKey = "tressette-login-token" // localStorage key for the account login token
+const loginNameKey = "tressette-login-name" // localStorage key for the username of the login
+
+let availableDeclarations = [] // Declarations the server says we can still make this round
 
 
 const rules_text = `<h2>Rules</h2>
@@ -62,14 +47,24 @@ const rules_text = `<h2>Rules</h2>
     <p>The first player to play a card leads the trick. The next player must follow suit if possible. Only cards of the same suit matter when calculating the winner of the trick.
     If a player cannot follow suit, they can play any card but it will never win the trick.</p>
     <p>The player who wins the trick leads the next trick. The game continues until all cards have been played.</p>
-    <p>Players also have the option to declare special combinations of cards for additional points before their first turn.</p>
+    <p>After the deal everyone may declare special combinations of cards for additional points at the same time. Once every player has confirmed they have nothing more to declare (or time runs out), the declarations are announced in playing order and the first trick begins.</p>
     <p>This means everyone knows that the a certain player has certain cards in their hand, but than that player's team gets additional points.</p>
     <p>Declarations are:</p>
     <p>Napola means that the player has 3, 2 and 1 of the same suit. The player gets 3 points for this declaration.</p>
     <p>Three of a kind means that the player has 3 cards of the same rank (only cards with ranks 3, 2, 1 are valid for this declaration). The player gets 3 points for this declaration.</p>
     <p>Four of a kind means that the player has 4 cards of the same rank (only cards with ranks 3, 2, 1 are valid for this declaration). The player gets 4 points for this declaration.</p>
-    <p>The game is supposed to be played in silence.</p>
+    <p>The game is supposed to be played in silence, except for three signals the player leading a trick may give with their card:
+    busso (knock: play your best card of this suit back to me), striscio (slide: I hold more cards of this suit) and volo (this is my last card of this suit).
+    Silent tables turn the signals off.</p>
     <p>Game ends when one of the teams reaches the points goal.</p>
+    <p>When playing a perdere everyone plays alone and the goal is to take as few points as possible. There are no declarations.
+    Whoever takes every point in a round (cappotto) scores nothing and everyone else gets those points instead.
+    A player who reaches the points goal is out, and the last player left wins.</p>
+    <p>When playing a chiamare there are no fixed teams. Each round the first player calls a card they don't hold, and whoever holds it is their secret partner
+    until that card is played. Everyone scores their side's points. A caller holding all four 3s may play alone and scores double.</p>
+    <p>Briscola uses the same deck but a different ranking: 1, 3, 13, 12, 11, 7, 6, 5, 4, 2. Aces are worth 11, 3s 10, 13s 4, 12s 3 and 11s 2.
+    Everyone holds three cards and draws after each trick. The card turned up after the deal sets the trump suit and goes last in the stock.
+    There is no need to follow suit; the highest trump wins the trick, otherwise the highest card of the led suit. More than 60 of the 120 points wins the hand, and two hands win the game.</p>
     <p>Have fun!</p>
     <p>For more information about the game, visit the <a href="https://en.wikipedia.org/wiki/Tressette" target="_blank">Wikipedia page</a>.</p>
 `
@@ -79,17 +74,33 @@ const rules_text = `<h2>Rules</h2>
 const statusMessage = document.getElementById("status-message")
 const playerHandDiv = document.getElementById("player-hand")
 const currentTrickDiv = document.getElementById("current-trick")
-const team1ScoreCurrentRoundSpan = document.getElementById("team1-score-current-round")
-const team2ScoreCurrentRoundSpan = document.getElementById("team2-score-current-round")
-const team1ScoreTotalSpan = document.getElementById("team1-score-total")
-const team2ScoreTotalSpan = document.getElementById("team2-score-total")
+const team3ScoreDiv = document.getElementById("team3-score")
+const team4ScoreDiv = document.getElementById("team4-score")
 const myPlayerNameSpan = document.getElementById("my-player-name")
 const teamToggle = document.getElementById("team-toggle")
 const pointsGoal = document.getElementById("points-goal-input")
+const playerCountInput = document.getElementById("player-count-input")
+const mortoRuleInput = document.getElementById("morto-rule-input")
+const rulesetInput = document.getElementById("ruleset-input")
+const spectatorHandDelayInput = document.getElementById("spectator-hand-delay-input")
+const bestOfInput = document.getElementById("best-of-input")
+const silentInput = document.getElementById("silent-input")
+const roundingInput = document.getElementById("rounding-input")
+const cappottoBonusInput = document.getElementById("cappotto-bonus-input")
+const excludeDeclarationsInput = document.getElementById("exclude-declarations-input")
+const turnTimeoutInput = document.getElementById("turn-timeout-input")
+const timeBankInput = document.getElementById("time-bank-input")
+const turnTimerDiv = document.getElementById("turn-timer")
+const scoreSheetTable = document.getElementById("score-sheet")
+const matchInfoDiv = document.getElementById("match-info")
+const signalPicker = document.getElementById("signal-picker")
+const signalInfo = document.getElementById("signal-info")
 const pointsGoalDisplay = document.getElementById("points-goal")
 const declarationArea = document.getElementById("declaration-button-area")
 const declarationsSection = document.getElementById("declarations-section")
 const declarationInfo = document.getElementById("declaration-info")
+const callSection = document.getElementById("call-section")
+const trumpCardDiv = document.getElementById("trump-card")
 const rulesSection = document.getElementById("rules")
 const rulesButton = document.getElementById("rules-button")
 
@@ -130,10 +141,36 @@ const playerNameInput = document.getElementById("player-name-input")
 const createGameButton = document.getElementById("create-game-button")
 const createdGameCodeDisplay = document.getElementById("created-game-code-display")
 const joinGameCodeInput = document.getElementById("join-game-code-input")
+const joinSeatTokenInput = document.getElementById("join-seat-token-input")
 const joinGameButton = document.getElementById("join-game-button")
+const spectateGameButton = document.getElementById("spectate-game-button")
+const quickMatchButton = document.getElementById("quick-match-button")
+const leaveQueueButton = document.getElementById("leave-queue-button")
+const queueStatus = document.getElementById("queue-status")
+const publicInput = document.getElementById("public-input")
+const rankedInput = document.getElementById("ranked-input")
+const leaderboardList = document.getElementById("leaderboard-list")
+const lobbyListDiv = document.getElementById("lobby-list")
+const refreshLobbiesButton = document.getElementById("refresh-lobbies-button")
+const usernameInput = document.getElementById("username-input")
+const passwordInput = document.getElementById("password-input")
+const loginForm = document.getElementById("login-form")
+const loggedInDiv = document.getElementById("logged-in")
+const accountNameDisplay = document.getElementById("account-name")
+const chatSection = document.getElementById("chat")
+const chatMessagesDiv = document.getElementById("chat-messages")
+const chatChannelInput = document.getElementById("chat-channel")
+const chatInput = document.getElementById("chat-input")
+const chatSendButton = document.getElementById("chat-send-button")
 const gameCodeDisplay = document.getElementById("game-code-display")
 const waitingStatus = document.getElementById("waiting-status")
 const lobbyPlayersDiv = document.getElementById("lobby-players")
+const lobbyBotControls = document.getElementById("lobby-bot-controls")
+const addRedBotButton = document.getElementById("add-red-bot-button")
+const addBlueBotButton = document.getElementById("add-blue-bot-button")
+const lockSeatsButton = document.getElementById("lock-seats-button")
+const randomizeSeatsButton = document.getElementById("randomize-seats-button")
+const startGameButton = document.getElementById("start-game-button")
 
 const suitOrder = { Bastoni: 1, Kope: 2, Denari: 3, Spade: 4 }
 
@@ -143,6 +180,7 @@ const playerPositions = {}
 
 document.addEventListener("DOMContentLoaded", () => {
     // Connect WebSocket on load, but don't join immediately
+    showAccount()
     connectWebSocket()
 
     // Add event listeners for create/join buttons
@@ -153,6 +191,29 @@ document.addEventListener("DOMContentLoaded", () => {
     if (joinGameButton) {
         joinGameButton.addEventListener("click", joinGame)
     }
+    if (spectateGameButton) {
+        spectateGameButton.addEventListener("click", spectateGame)
+    }
+    quickMatchButton.addEventListener("click", quickMatch)
+    leaveQueueButton.addEventListener("click", () => sendMessage("leave_queue", {}))
+    refreshLobbiesButton.addEventListener("click", loadLobbies)
+    document.getElementById("login-button").addEventListener("click", () => submitAccount("/api/login"))
+    document.getElementById("register-button").addEventListener("click", () => submitAccount("/api/accounts"))
+    document.getElementById("logout-button").addEventListener("click", logout)
+    signalPicker.querySelectorAll("button").forEach((button) => {
+        button.addEventListener("click", () => selectSignal(button.dataset.signal))
+    })
+    chatSendButton.addEventListener("click", sendChat)
+    chatInput.addEventListener("keydown", (event) => {
+        if (event.key === "Enter") {
+            sendChat()
+        }
+    })
+    addRedBotButton.addEventListener("click", () => sendMessage("add_bot", { team: 1 }))
+    addBlueBotButton.addEventListener("click", () => sendMessage("add_bot", { team: 2 }))
+    lockSeatsButton.addEventListener("click", () => sendMessage("lock_seats", { locked: !seatsLocked }))
+    randomizeSeatsButton.addEventListener("click", () => sendMessage("randomize_seats", {}))
+    startGameButton.addEventListener("click", () => sendMessage("start_game", {}))
 
     // Initial UI state
     showSection("initial-section")
@@ -175,13 +236,23 @@ document.addEventListener("DOMContentLoaded", () => {
 function connectWebSocket() {
     // Determine WebSocket protocol (ws or wss)
     const wsProtocol = window.location.protocol === "https:" ? "wss:" : "ws:"
-    const wsUrl = `${wsProtocol}//${window.location.host}/ws`
+    const loginToken = localStorage.getItem(loginTokenKey)
+    // Browsers can't set headers on a WebSocket, so the login token goes in the URL
+    const wsUrl = `${wsProtocol}//${window.location.host}/ws` + (loginToken ? `?token=${encodeURIComponent(loginToken)}` : "")
 
     ws = new WebSocket(wsUrl)
+    let opened = false
 
     ws.onopen = () => {
+        opened = true
         console.log("WebSocket connection established")
         statusMessage.textContent = "Connected. Create or join a game."
+        const token = localStorage.getItem(sessionTokenKey)
+        if (token) {
+            // Try to reclaim our seat in a game we were disconnected from
+            rejoining = true
+            sendMessage("rejoin_game", { token })
+        }
     }
 
     ws.onmessage = (event) => {
@@ -202,6 +273,18 @@ function connectWebSocket() {
 
     ws.onclose = () => {
         console.log("WebSocket connection closed")
+        if (!opened && loginToken) {
+            // The server turned the login down (e.g. logged out elsewhere): carry on as a guest
+            forgetLogin()
+            connectWebSocket()
+            return
+        }
+        if (localStorage.getItem(sessionTokenKey)) {
+            // Keep the table on screen and try to reclaim the seat
+            statusMessage.textContent = "Connection lost. Reconnecting..."
+            setTimeout(connectWebSocket, 2000)
+            return
+        }
         statusMessage.textContent = "Disconnected. Please refresh to reconnect."
         showSection("initial-section")
     }
@@ -221,6 +304,75 @@ function sendMessage(type, payload) {
     }
 }
 
+// --- Accounts ---
+
+// Log in or register, then reconnect so the server ties the connection to the account
+function submitAccount(url) {
+    const username = usernameInput.value.trim()
+    const password = passwordInput.value
+    if (!username || !password) {
+        alert("Please enter a username and password.")
+        return
+    }
+    fetch(url, { method: "POST", headers: { "Content-Type": "application/json" }, body: JSON.stringify({ username, password }) })
+        .then((response) => {
+            if (!response.ok) {
+                return response.text().then((text) => Promise.reject(new Error(text.trim())))
+            }
+            return response.json()
+        })
+        .then((account) => {
+            if (url === "/api/accounts") {
+                // Registered; log in with the same details
+                submitAccount("/api/login")
+                return
+            }
+            localStorage.setItem(loginTokenKey, account.token)
+            localStorage.setItem(loginNameKey, account.username)
+            passwordInput.value = ""
+            showAccount()
+            reconnect()
+        })
+        .catch((error) => alert(error.message))
+}
+
+function logout() {
+    const token = localStorage.getItem(loginTokenKey)
+    fetch("/api/logout", { method: "POST", headers: { Authorization: `Bearer ${token}` } }).catch((error) => console.error("Could not log out:", error))
+    forgetLogin()
+    reconnect()
+}
+
+function forgetLogin() {
+    localStorage.removeItem(loginTokenKey)
+    localStorage.removeItem(loginNameKey)
+    showAccount()
+}
+
+// Registered players always play under their username
+function showAccount() {
+    const username = localStorage.getItem(loginNameKey)
+    if (username) {
+        accountNameDisplay.textContent = username
+        playerNameInput.value = username
+        playerNameInput.disabled = true
+        loginForm.classList.add("hidden")
+        loggedInDiv.classList.remove("hidden")
+    } else {
+        playerNameInput.disabled = false
+        loginForm.classList.remove("hidden")
+        loggedInDiv.classList.add("hidden")
+    }
+}
+
+function reconnect() {
+    if (ws) {
+        ws.onclose = null
+        ws.close()
+    }
+    connectWebSocket()
+}
+
 // --- Game Creation and Joining ---
 
 function createGame() {
@@ -241,7 +393,11 @@ function createGame() {
     }
     const team = selectedTeam.id === "red" ? 1 : 2 // Map team ID to team number
     myPlayerName = name
-    sendMessage("create_game", { name, desired_team: team, points_goal: parseInt(pointsGoalValue) }) // Send team ID to server
+    const playerCount = parseInt(playerCountInput.value)
+    const mortoRule = mortoRuleInput.value
+    const ruleset = rulesetInput.value
+    const spectatorHandDelay = parseInt(spectatorHandDelayInput.value)
+    sendMessage("create_game", { name, desired_team: team, points_goal: parseInt(pointsGoalValue), player_count: playerCount, morto_rule: mortoRule, ruleset, spectator_hand_delay: spectatorHandDelay, silent: silentInput.checked, turn_timeout: parseInt(turnTimeoutInput.value), time_bank: parseInt(timeBankInput.value), rounding: roundingInput.value, cappotto_bonus: parseInt(cappottoBonusInput.value) || 0, exclude_declarations: excludeDeclarationsInput.checked, best_of: parseInt(bestOfInput.value), public: publicInput.checked, ranked: rankedInput.checked }) // Send team ID to server
     waitingStatus.textContent = "Creating game..."
     // Clear join code input if user clicks create after typing in join
     if (joinGameCodeInput) joinGameCodeInput.value = ""
@@ -264,8 +420,9 @@ function joinGame() {
         return
     }
     const team = desired_team.id === "red" ? 1 : 2 // Map team ID to team number
+    const seat_token = joinSeatTokenInput ? joinSeatTokenInput.value.trim() : "" // Seat kept at a tournament table
     myPlayerName = name
-    sendMessage("join_game", { name, game_code: gameCode, desired_team: team }) // Send team ID to server
+    sendMessage("join_game", { name, game_code: gameCode, desired_team: team, seat_token }) // Send team ID to server
     showSection("waiting-section") // Switch to waiting section on attempting join
     waitingStatus.textContent = "Joining game..."
     gameCodeDisplay.textContent = gameCode
@@ -273,6 +430,91 @@ function joinGame() {
     if (createdGameCodeDisplay) createdGameCodeDisplay.value = ""
 }
 
+// Wait in the queue for a four-player table with the default rules
+function quickMatch() {
+    const name = playerNameInput.value.trim()
+    if (!name) {
+        alert("Please enter your name.")
+        return
+    }
+    myPlayerName = name
+    sendMessage("quick_match", { name })
+}
+
+function handleQueueUpdate(payload) {
+    if (payload.queued) {
+        queueStatus.textContent = `Waiting for a quick match (${payload.waiting}/4)...`
+        quickMatchButton.classList.add("hidden")
+        leaveQueueButton.classList.remove("hidden")
+    } else {
+        queueStatus.textContent = ""
+        quickMatchButton.classList.remove("hidden")
+        leaveQueueButton.classList.add("hidden")
+    }
+}
+
+function handleQuickMatchFound(payload) {
+    handleQueueUpdate({ queued: false })
+    gameCodeDisplay.textContent = payload.game_code
+    showSection("waiting-section")
+    waitingStatus.textContent = "Table found. Starting game..."
+}
+
+// List the public lobbies that still have a free seat
+function loadLobbies() {
+    fetch("/api/lobbies")
+        .then((response) => response.json())
+        .then((payload) => {
+            lobbyListDiv.innerHTML = ""
+            if (payload.lobbies.length === 0) {
+                lobbyListDiv.textContent = "No open games right now."
+                return
+            }
+            payload.lobbies.forEach((lobby) => {
+                const row = document.createElement("div")
+                row.className = "lobby-listing"
+                const timer = lobby.turn_timeout > 0 ? `, ${lobby.turn_timeout}s per move` : ""
+                const series = lobby.best_of > 1 ? `, best of ${lobby.best_of}` : ""
+                row.textContent = `${lobby.host}'s ${lobby.ranked ? "ranked " : ""}table: ${lobby.ruleset}, ${lobby.player_count} players, ${lobby.points_goal} points${series}${timer} (${lobby.free_seats.length} free)`
+                const button = document.createElement("button")
+                button.textContent = "Join"
+                button.addEventListener("click", () => {
+                    joinGameCodeInput.value = lobby.game_code
+                    joinGame()
+                })
+                row.appendChild(button)
+                lobbyListDiv.appendChild(row)
+            })
+        })
+        .catch((error) => console.error("Could not load lobbies:", error))
+}
+
+// Show the highest rated players
+function loadLeaderboard() {
+    fetch("/api/leaderboard?limit=10")
+        .then((response) => response.json())
+        .then((ratings) => {
+            leaderboardList.innerHTML = ""
+            ratings.forEach((rating) => {
+                const item = document.createElement("li")
+                item.textContent = `${rating.username}: ${rating.rating} (${rating.games} games)`
+                leaderboardList.appendChild(item)
+            })
+        })
+        .catch((error) => console.error("Could not load leaderboard:", error))
+}
+
+function spectateGame() {
+    const name = playerNameInput.value.trim()
+    const gameCode = joinGameCodeInput.value.trim().toUpperCase()
+    if (!gameCode) {
+        alert("Please enter the game code to watch.")
+        return
+    }
+    spectating = true
+    sendMessage("spectate_game", { name, game_code: gameCode })
+}
+
 // --- Message Handling ---
 
 function handleMessage(message) {
@@ -285,9 +527,49 @@ function handleMessage(message) {
         case "game_created":
             handleGameCreated(message.payload)
             break
+        case "session":
+            localStorage.setItem(sessionTokenKey, message.payload.token)
+            break
+        case "spectate_start":
+            handleSpectateStart(message.payload)
+            break
+        case "spectator_hands":
+            handleSpectatorHands(message.payload)
+            break
+        case "chat":
+            appendChatMessage(message.payload)
+            break
+        case "chat_history":
+            chatMessagesDiv.innerHTML = ""
+            message.payload.messages.forEach((m) => appendChatMessage(m))
+            break
+        case "player_muted":
+            handlePlayerMuted(message.payload)
+            break
+        case "game_snapshot":
+            handleGameSnapshot(message.payload)
+            break
+        case "player_disconnected":
+            handlePlayerDisconnected(message.payload)
+            break
+        case "player_reconnected":
+            handlePlayerReconnected(message.payload)
+            break
+        case "card_drawn":
+            handleCardDrawn(message.payload)
+            break
         case "lobby_update":
             handleLobbyUpdate(message.payload)
             break
+        case "queue_update":
+            handleQueueUpdate(message.payload)
+            break
+        case "quick_match_found":
+            handleQuickMatchFound(message.payload)
+            break
+        case "swap_requested":
+            handleSwapRequested(message.payload)
+            break
         case "join_error":
             handleJoinError(message.payload)
             break
@@ -298,7 +580,7 @@ function handleMessage(message) {
             handleDealHand(message.payload)
             break
         case "your_turn":
-            handleYourTurn()
+            handleYourTurn(message.payload)
             break
         case "game_state_update":
             handleGameState(message.payload)
@@ -309,12 +591,45 @@ function handleMessage(message) {
         case "trick_end":
             handleTrickEnd(message.payload)
             break
+        case "ready_check":
+            handleReadyCheck(message.payload)
+            break
+        case "turn_timeout":
+            handleTurnTimeout(message.payload)
+            break
+        case "shuffle_commitment":
+            shuffleCommitment = message.payload.commitment
+            break
         case "round_end":
             handleRoundEnd(message.payload)
             break
         case "game_over":
             handleGameOver(message.payload)
             break
+        case "match_update":
+            handleMatchUpdate(message.payload)
+            break
+        case "table_closed":
+            handleTableClosed(message.payload)
+            break
+        case "player_eliminated":
+            handlePlayerEliminated(message.payload)
+            break
+        case "call_request":
+            handleCallRequest()
+            break
+        case "card_called":
+            handleCardCalled(message.payload)
+            break
+        case "partner_revealed":
+            handlePartnerRevealed(message.payload)
+            break
+        case "declaration_phase":
+            handleDeclarationPhase(message.payload)
+            break
+        case "declaration_recorded":
+            handleDeclarationRecorded(message.payload)
+            break
         case "declaration_confirmation":
             handleDeclarationConfirmation(message.payload)
             break
@@ -339,15 +654,69 @@ function handleGameCreated(payload) {
 
 function handleLobbyUpdate(payload) {
     lobbyPlayersDiv.innerHTML = "" // Clear previous list
-    payload.players.forEach((player) => {
-        const playerElement = document.createElement("div")
-        playerElement.textContent = player.name + (player.name === myPlayerName ? " (You)" : "")
-        lobbyPlayersDiv.appendChild(playerElement)
+    seatsLocked = payload.seats_locked
+    // Four players in two teams play Red against Blue; otherwise every seat is its own team
+    const teamCount = new Set(payload.seats.map((s) => s.team)).size
+    const teamName = (team) =>
+        payload.seats.length === 4 && teamCount === 2 ? (team === 1 ? "Red" : "Blue") : `Team ${team}`
+    payload.seats.forEach((seat) => {
+        const seatElement = document.createElement("div")
+        seatElement.className = "lobby-seat"
+        const player = payload.players.find((p) => p.id === seat.player_id)
+        const isMe = player && player.name === myPlayerName
+        let label = `Seat ${seat.seat + 1} (${teamName(seat.team)}): `
+        if (!player) {
+            label += "free"
+        } else {
+            label += player.name + (player.is_bot ? " [bot]" : "") + (isMe ? " (You)" : "")
+        }
+        seatElement.textContent = label
+        if (!seatsLocked && !isMe) {
+            const button = document.createElement("button")
+            if (!player) {
+                button.textContent = "Sit here"
+                button.addEventListener("click", () => sendMessage("choose_seat", { seat: seat.seat }))
+            } else {
+                button.textContent = "Swap"
+                button.addEventListener("click", () => sendMessage("swap_request", { seat: seat.seat }))
+            }
+            seatElement.appendChild(button)
+        }
+        lobbyPlayersDiv.appendChild(seatElement)
     })
-    waitingStatus.textContent = `Waiting for players (${payload.players.length}/4)...`
+    // The first human in the lobby is the host: they add bots, manage the seats and start the game
+    const host = payload.players.find((p) => p.id === payload.host_id)
+    isHost = host && host.name === myPlayerName
+    if (isHost) {
+        lobbyBotControls.classList.remove("hidden")
+    } else {
+        lobbyBotControls.classList.add("hidden")
+    }
+    lockSeatsButton.textContent = seatsLocked ? "Unlock Seats" : "Lock Seats"
+    const full = payload.players.length >= payload.player_count
+    startGameButton.disabled = !full
+    if (full) {
+        waitingStatus.textContent = isHost ? "Every seat is taken. Start the game when ready." : "Waiting for the host to start the game..."
+    } else {
+        waitingStatus.textContent = `Waiting for players (${payload.players.length}/${payload.player_count})...`
+    }
+}
+
+function handleSwapRequested(payload) {
+    if (confirm(`${payload.name} wants to swap seats with you (seat ${payload.seat + 1}). Accept?`)) {
+        sendMessage("swap_request", { seat: payload.seat })
+    }
 }
 
 function handleJoinError(payload) {
+    if (rejoining) {
+        // Our old seat is gone; start fresh without bothering the user
+        rejoining = false
+        localStorage.removeItem(sessionTokenKey)
+        showSection("initial-section")
+        return
+    }
+    spectating = false
     console.error("Failed to join game:", payload.message)
     alert(`Join Error: ${payload.message}`)
     showSection("initial-section") // Go back to initial screen
@@ -359,6 +728,9 @@ function handleGenericError(payload) {
 }
 
 function handleGameStart(payload) {
+    if (spectating) {
+        myPlayerName = payload.players[0].name // Spectators watch from the first seat
+    }
     myPlayerId = findPlayerIdByName(payload.players, myPlayerName) // Find our player ID based on name
     playerPositions[myPlayerId] = "player-bottom" // Assign position for our hand
     if (!myPlayerId) {
@@ -376,45 +748,272 @@ function handleGameStart(payload) {
     }
     setupOpponentNames(payload.players, payload.teams)
     pointsGoalDisplay.textContent = `Points Goal: ${payload.points_goal}`
-    canDeclare = true
+    gameMode = payload.mode
+    gameOver = false // A rematch starts at the same table
+    eliminatedPlayers = []
+    declarationsAllowed = payload.declarations
+    signalsAllowed = payload.signals
+    canDeclare = false
+    team3ScoreDiv.classList.toggle("hidden", payload.teams.length < 3)
+    team4ScoreDiv.classList.toggle("hidden", payload.teams.length < 4)
+    setScoreLabels(payload.players, payload.teams)
+    setTotalScores(payload.teams.map(() => 0))
     resetScores()
 }
 
+function handleGameSnapshot(payload) {
+    rejoining = false
+    if (!spectating) {
+        myPlayerName = payload.players.find((p) => p.id === payload.player_id).name
+    }
+    handleGameStart(payload)
+    myPlayerId = payload.player_id || myPlayerId
+    shuffleCommitment = payload.commitment || null
+    const sheets = payload.score_sheet || []
+    renderScoreSheet(payload.game_state === "RoundOver" ? sheets[sheets.length - 1] : null)
+    canDeclare = false
+    handCards = payload.hand
+    renderHand(payload.hand)
+    clearTrickDisplay()
+    renderTrick(payload.cards_on_table)
+    trickCards = payload.cards_on_table
+    stockRemaining = payload.stock_remaining
+    teamsInfo.forEach((team) => {
+        team.score = 0
+        updateScoresAfterDeclarationConfirmation({ team_id: team.id, points: payload.scores[team.team_number - 1] })
+    })
+    setTotalScores(payload.total_scores)
+    payload.eliminated.forEach((playerId) => markEliminated(playerId))
+    if (payload.call) {
+        handleCardCalled(payload.call)
+    }
+    if (payload.partner_id && payload.call) {
+        handlePartnerRevealed({ caller_id: payload.call.caller_id, partner_id: payload.partner_id })
+    }
+    renderTrump(payload.trump)
+    statusMessage.textContent = "Reconnected."
+}
+
+function handleSpectateStart(payload) {
+    spectating = true
+    handleGameSnapshot(payload)
+    statusMessage.textContent = "Spectating."
+}
+
+function handleSpectatorHands(payload) {
+    // Hands as they were a while ago; the bottom seat uses the normal hand area
+    Object.entries(payload.hands).forEach(([playerId, hand]) => {
+        if (playerId === myPlayerId) {
+            renderHand(hand)
+            return
+        }
+        const area = document.getElementById(playerPositions[playerId])
+        if (!area) {
+            return
+        }
+        let revealed = area.querySelector(".revealed-hand")
+        if (!revealed) {
+            revealed = document.createElement("div")
+            revealed.classList.add("revealed-hand")
+            area.appendChild(revealed)
+        }
+        revealed.innerHTML = ""
+        hand.sort(compareCards).forEach((card) => revealed.appendChild(createCardElement(card, true)))
+    })
+}
+
+function handlePlayerDisconnected(payload) {
+    const player = findPlayerInTeams(payload.player_id)
+    const name = player ? player.name : payload.player_id
+    statusMessage.textContent = `${name} disconnected. Waiting ${payload.grace_period_seconds}s for them to return...`
+}
+
+function handlePlayerReconnected(payload) {
+    if (payload.player_id === myPlayerId) {
+        return
+    }
+    const player = findPlayerInTeams(payload.player_id)
+    const name = player ? player.name : payload.player_id
+    statusMessage.textContent = `${name} reconnected.`
+}
+
 function handleDealHand(payload) {
     statusMessage.textContent = "Cards dealt. Waiting for first turn."
+    declarationArea.innerHTML = "" // Drop the ready button of the last round
+    renderScoreSheet(null)
+    if (roundOver) {
+        roundOver = false
+        roundOverPayload = null
+        resetScores() // Reset scores for the next round
+    }
+    partnerId = null
+    document.querySelectorAll(".partner").forEach((el) => el.classList.remove("partner"))
     handCards = payload.hand // Store hand cards for later use
     renderHand(payload.hand)
+    renderTrump(payload.trump)
 }
 
-function handleYourTurn() {
-    statusMessage.textContent = "Your turn!"
-    highlightPlayableCards()
-    renderDeclarations()
-    removeDeclarationInfo()
+function renderTrump(card) {
+    // Briscola shows the turned-up card that sets the trump suit
+    trumpCardDiv.innerHTML = ""
+    trumpCardDiv.classList.toggle("hidden", !card)
+    if (card) {
+        trumpCardDiv.append("Trump: ", createCardElement(card, true))
+    }
+}
+
+function handleYourTurn(payload) {
+    statusMessage.textContent = payload.time_bank_seconds ? `Your turn! (time bank: ${payload.time_bank_seconds}s)` : "Your turn!"
+    highlightPlayableCards(payload.valid_moves || [])
+    // Signals go with the card that leads a trick
+    selectSignal("")
+    signalPicker.classList.toggle("hidden", !signalsAllowed || trickCards.length > 0)
 }
 
 function handleGameState(payload) {
+    if (afterTrick && payload.cards_on_table.length === 0) {
+        // The server has finished showing the last trick
+        afterTrick = false
+        playDisabled = false
+        clearTrickDisplay()
+    }
     const currentPlayer = teamsInfo
         .find((t) => t.players.some((p) => p.id === payload.current_player_id))
         .players.find((p) => p.id === payload.current_player_id)
     document.querySelectorAll(".current-player").forEach((el) => el.classList.remove("current-player")) // Remove previous highlights
     document.getElementById(playerPositions[payload.current_player_id]).querySelector(".player-name").classList.add("current-player") // Highlight current player
     const playerName = currentPlayer.name || payload.current_player_id // Fallback to ID if name not found
-    if (currentPlayer.id !== myPlayerId && !afterTrick) {
+    if (payload.game_state !== "Declaring" && canDeclare) {
+        canDeclare = false // The declaration phase is over
+        renderDeclarations()
+    }
+    if (payload.game_state === "Declaring") {
+        // handleDeclarationPhase keeps the status message up to date
+    } else if (payload.game_state === "Calling" && currentPlayer.id !== myPlayerId) {
+        statusMessage.textContent = `${playerName} is calling a partner...`
+    } else if (currentPlayer.id !== myPlayerId || spectating) {
         statusMessage.textContent = `${playerName}'s turn` // Update based on actual name later
-    } else if (afterTrick) {
-        afterTrick = false // Reset after trick flag
     }
     renderTrick(payload.cards_on_table)
     trickCards = payload.cards_on_table // Store cards in the current trick
+    stockRemaining = payload.stock_remaining
+    showSignal(payload.signal, payload.signal_player_id)
+    startTurnTimer(payload.turn_seconds || 0, playerName)
+}
+
+// startTurnTimer counts down the time the current player has left; 0 hides the timer.
+function startTurnTimer(seconds, playerName) {
+    clearInterval(turnTimerInterval)
+    turnTimerInterval = null
+    turnTimerDiv.textContent = ""
+    turnTimerDiv.classList.remove("running-out")
+    if (seconds <= 0) {
+        return
+    }
+    const deadline = Date.now() + seconds * 1000
+    const tick = () => {
+        const left = Math.max(0, Math.ceil((deadline - Date.now()) / 1000))
+        turnTimerDiv.textContent = `${playerName}: ${left}s left`
+        turnTimerDiv.classList.toggle("running-out", left <= 10)
+        if (left === 0) {
+            clearInterval(turnTimerInterval)
+        }
+    }
+    tick()
+    turnTimerInterval = setInterval(tick, 1000)
+}
+
+// renderScoreSheet shows the breakdown of a round's scores, one column per team.
+function renderScoreSheet(sheet) {
+    scoreSheetTable.innerHTML = ""
+    scoreSheetTable.classList.toggle("hidden", !sheet)
+    if (!sheet) {
+        return
+    }
+    const thirds = (n) => (n % 3 === 0 ? `${n / 3}` : `${n}/3`)
+    const rows = [
+        ["Aces", (t) => t.aces],
+        ["Figures", (t) => t.figures],
+        ["Cards", (t) => thirds(t.card_points)],
+        ["Last trick", (t) => thirds(t.last_trick_bonus)],
+        ["Morto", (t) => thirds(t.morto_points)],
+        ["Declarations", (t) => thirds(t.declaration_points)],
+        ["Adjustment", (t) => thirds(t.adjustment)],
+        ["Cappotto", (t) => thirds(t.cappotto_bonus)],
+        ["Thirds carried in", (t) => t.thirds_carried_in],
+        ["Thirds from last trick", (t) => t.thirds_received],
+        ["Thirds carried over", (t) => t.thirds_carried_out],
+        ["Thirds lost", (t) => t.thirds_lost],
+        ["Round", (t) => t.round_points],
+        ["Total", (t) => t.total_score],
+    ]
+    const header = document.createElement("tr")
+    header.innerHTML = `<th>Round ${sheet.round}</th>`
+    sheet.teams.forEach((team) => {
+        const label = document.querySelector(`#team${team.team_number}-score span`)
+        const th = document.createElement("th")
+        th.textContent = label ? label.textContent.replace(":", "").trim() : `Team ${team.team_number}`
+        header.appendChild(th)
+    })
+    scoreSheetTable.appendChild(header)
+    rows.forEach(([name, value]) => {
+        const tr = document.createElement("tr")
+        const th = document.createElement("th")
+        th.textContent = name
+        tr.appendChild(th)
+        sheet.teams.forEach((team) => {
+            const td = document.createElement("td")
+            td.textContent = value(team)
+            tr.appendChild(td)
+        })
+        scoreSheetTable.appendChild(tr)
+    })
+}
+
+function handleTurnTimeout(payload) {
+    const player = findPlayerInTeams(payload.player_id)
+    const name = payload.player_id === myPlayerId && !spectating ? "You" : player ? player.name : payload.player_id
+    const times = payload.timeouts > 1 ? ` (${payload.timeouts} times this game)` : ""
+    const action = payload.called ? "called" : "played"
+    showRoundInfo(`${name} ran out of time${times}; ${payload.card.Rank} of ${payload.card.Suit} was ${action}.`)
+}
+
+function showSignal(signal, playerId) {
+    if (!signal) {
+        signalInfo.textContent = ""
+        return
+    }
+    const player = findPlayerInTeams(playerId)
+    const name = playerId === myPlayerId && !spectating ? "You" : player ? player.name : playerId
+    signalInfo.textContent = `${name}: ${signal}!`
+}
+
+function selectSignal(signal) {
+    selectedSignal = signal
+    signalPicker.querySelectorAll("button").forEach((button) => {
+        button.classList.toggle("selected", button.dataset.signal === signal)
+    })
+}
+
+function handleCardDrawn(payload) {
+    stockRemaining = payload.stock_remaining
+    // Briscola keeps draws private, so other players' cards are not sent
+    const card = payload.card ? `${payload.card.Rank} of ${payload.card.Suit}` : "a card"
+    if (payload.player_id === myPlayerId) {
+        handCards.push(payload.card)
+        renderHand(handCards)
+        statusMessage.textContent = `You drew ${card}. ${stockRemaining} cards left in the stock.`
+    } else {
+        const player = findPlayerInTeams(payload.player_id)
+        statusMessage.textContent = `${player ? player.name : payload.player_id} drew ${card}. ${stockRemaining} cards left in the stock.`
+    }
 }
 
 function handlePlayerPlayedCard(payload) {
     if (payload.player_id === myPlayerId) {
-        canDeclare = false // Disable declaration after playing a card
         removeCardFromHand(payload.card)
         removeHighlightedCards() // Remove highlight from all cards
-        renderDeclarations() // Update declarations section
+        signalPicker.classList.add("hidden")
     }
 }
 
@@ -431,26 +1030,7 @@ function handleTrickEnd(payload) {
     } else {
         statusMessage.textContent = `${playerName} won the trick!`
     }
-    // Clear the trick area after a short delay
-    setTimeout(() => {
-        clearTrickDisplay()
-        if (gameOver) {
-            return
-        }
-        if (payload.winner_id !== myPlayerId) {
-            statusMessage.textContent = `Waiting for ${playerName} to lead next trick.`
-        }
-        playDisabled = false // Re-enable play card action
-        if (roundOver) {
-            statusMessage.textContent = "Round over. Waiting for next round."
-            team1ScoreTotalSpan.textContent = `${roundOverPayload.team1_total_score}`
-            team2ScoreTotalSpan.textContent = `${roundOverPayload.team2_total_score}`
-            // Reset round over flag
-            roundOver = false
-            roundOverPayload = null // Clear the payload
-            resetScores() // Reset scores for the next round
-        }
-    }, 5000) // 2-second delay
+    // The trick stays on the table until the server moves on to the next one
     // Make a glow effect on the winning card after 500 ms
     setTimeout(() => {
         const winningCard = currentTrickDiv.querySelector(`[data-card-id="${payload.winner.card.Suit}-${payload.winner.card.Rank}"]`)
@@ -463,16 +1043,195 @@ function handleTrickEnd(payload) {
 function handleRoundEnd(payload) {
     roundOver = true // Set flag to indicate round has ended
     roundOverPayload = payload // Store the payload for round over
+    startTurnTimer(0)
+    setTotalScores(payload.total_scores)
+    renderScoreSheet(payload.score_sheet)
+    statusMessage.textContent = "Round over."
+    verifyShuffle(payload.seed)
+    if (payload.cappotto_player_id) {
+        const player = findPlayerInTeams(payload.cappotto_player_id)
+        const name = payload.cappotto_player_id === myPlayerId ? "You" : player ? player.name : payload.cappotto_player_id
+        showRoundInfo(`Cappotto! ${name} took every point, so everyone else gets them.`)
+    } else if (payload.morto) {
+        showRoundInfo(`The morto was ${payload.morto.Rank} of ${payload.morto.Suit}`)
+    }
+}
+
+// verifyShuffle checks the revealed seed against the commitment published before the deal.
+async function verifyShuffle(seed) {
+    if (!shuffleCommitment || !seed || !window.crypto || !crypto.subtle) {
+        return
+    }
+    const bytes = new Uint8Array(seed.match(/../g).map((h) => parseInt(h, 16)))
+    const digest = new Uint8Array(await crypto.subtle.digest("SHA-256", bytes))
+    const hash = Array.from(digest, (b) => b.toString(16).padStart(2, "0")).join("")
+    if (hash === shuffleCommitment) {
+        console.log(`Shuffle verified: seed ${seed} matches commitment ${hash}`)
+    } else {
+        showRoundInfo("Warning: the revealed shuffle seed does not match the commitment made before the deal!")
+    }
+}
+
+function handlePlayerEliminated(payload) {
+    markEliminated(payload.player_id)
+    if (payload.player_id === myPlayerId) {
+        statusMessage.textContent = `You reached ${payload.total_score} points and are out. You can keep watching the game.`
+        playerHandDiv.innerHTML = ""
+        handCards = []
+        return
+    }
+    const player = findPlayerInTeams(payload.player_id)
+    const name = player ? player.name : payload.player_id
+    statusMessage.textContent = `${name} reached ${payload.total_score} points and is out.`
+}
+
+function handleCallRequest() {
+    // Offer every card we don't hold, one row per suit
+    statusMessage.textContent = "Call a card to choose your secret partner."
+    callSection.innerHTML = "<h3>Call a partner</h3>"
+    const suits = ["Bastoni", "Kope", "Denari", "Spade"]
+    const ranks = ["3", "2", "1", "13", "12", "11", "7", "6", "5", "4"]
+    suits.forEach((suit) => {
+        const row = document.createElement("div")
+        row.classList.add("declarations-section-inner")
+        ranks.forEach((rank) => {
+            if (handCards.some((c) => c.Suit === suit && c.Rank === rank)) {
+                return
+            }
+            const cardElement = createCardElement({ Suit: suit, Rank: rank })
+            cardElement.style.cursor = "pointer"
+            cardElement.addEventListener("click", () => {
+                sendMessage("call_card", { suit, rank, alone: false })
+                callSection.style.display = "none"
+            })
+            row.appendChild(cardElement)
+        })
+        callSection.appendChild(row)
+    })
+    if (handCards.filter((c) => c.Rank === "3").length === 4) {
+        const aloneButton = document.createElement("button")
+        aloneButton.textContent = "Play alone"
+        aloneButton.addEventListener("click", () => {
+            sendMessage("call_card", { suit: "", rank: "", alone: true })
+            callSection.style.display = "none"
+        })
+        callSection.appendChild(aloneButton)
+    }
+    callSection.style.display = "block"
+}
+
+function handleCardCalled(payload) {
+    callSection.style.display = "none" // The server may have called for us when time ran out
+    const caller = findPlayerInTeams(payload.caller_id)
+    const name = payload.caller_id === myPlayerId ? "You" : caller ? caller.name : payload.caller_id
+    if (payload.alone) {
+        showRoundInfo(`${name} will play alone against everyone.`)
+        return
+    }
+    const card = `${payload.card.Rank} of ${payload.card.Suit}`
+    if (handCards.some((c) => c.Suit === payload.card.Suit && c.Rank === payload.card.Rank)) {
+        partnerId = myPlayerId
+        showRoundInfo(`${name} called the ${card}. You hold it, so you are their secret partner!`)
+    } else {
+        showRoundInfo(`${name} called the ${card}.`)
+    }
+}
+
+function handlePartnerRevealed(payload) {
+    partnerId = payload.partner_id
+    ;[payload.caller_id, payload.partner_id].forEach((playerId) => {
+        const position = playerPositions[playerId]
+        if (position) {
+            document.getElementById(position).querySelector(".player-name").classList.add("partner")
+        }
+    })
+    const caller = findPlayerInTeams(payload.caller_id)
+    const partner = findPlayerInTeams(payload.partner_id)
+    showRoundInfo(`${partner ? partner.name : payload.partner_id} is playing with ${caller ? caller.name : payload.caller_id}.`)
+}
+
+function showRoundInfo(message) {
+    declarationInfo.textContent = message
+    declarationInfo.style.display = "block"
+    declarationInfo.classList.add("declaration-info")
+    setTimeout(() => {
+        removeDeclarationInfo()
+    }, 5000)
+}
+
+function markEliminated(playerId) {
+    if (!eliminatedPlayers.includes(playerId)) {
+        eliminatedPlayers.push(playerId)
+    }
+    const position = playerPositions[playerId]
+    if (position) {
+        document.getElementById(position).querySelector(".player-name").classList.add("eliminated")
+    }
 }
 
 function handleGameOver(payload) {
     // TODO: show team name instead of ID
     gameOver = true // Set flag to indicate game is over
-    statusMessage.textContent = `Game Over! Winning Team: ${payload.winning_team_id}. Final Score: T1 ${payload.final_score_t1} - T2 ${payload.final_score_t2}`
+    const finalScores = payload.final_scores.map((score, i) => `T${i + 1} ${score}`).join(" - ")
+    const winningTeam = teamsInfo.find((t) => t.team_number === payload.winning_team_number)
+    if (gameMode === "a_perdere" && winningTeam) {
+        statusMessage.textContent = `Game Over! ${winningTeam.players[0].name} is the last one standing. Final Score: ${finalScores}`
+    } else {
+        statusMessage.textContent = `Game Over! Winning Team: ${payload.winning_team_number}. Final Score: ${finalScores}`
+    }
+    document.querySelectorAll(".eliminated").forEach((el) => el.classList.remove("eliminated"))
     playerHandDiv.innerHTML = "<p>Game Over</p>"
     currentTrickDiv.innerHTML = ""
+    // The table stays together until someone declines the rematch (see handleMatchUpdate)
+}
+
+// handleMatchUpdate shows the state of the series and asks for a rematch after every game.
+function handleMatchUpdate(payload) {
+    const wins = payload.wins.map((w, i) => `T${i + 1} ${w}`).join(" - ")
+    let text = payload.best_of > 1 ? `Best of ${payload.best_of}: ${wins}` : ""
+    if (payload.tiebreak) {
+        text += " (tiebreak)"
+    }
+    if (payload.finished && payload.best_of > 1) {
+        text += `. Team ${payload.winning_team} wins the match!`
+    }
+    matchInfoDiv.textContent = text
+    matchInfoDiv.classList.toggle("hidden", !text)
+
+    declarationArea.innerHTML = ""
+    if (payload.pending_player_ids.includes(myPlayerId) && !spectating) {
+        const next = payload.finished ? "Rematch" : "Next game"
+        const acceptButton = document.createElement("button")
+        acceptButton.textContent = next
+        acceptButton.addEventListener("click", () => {
+            sendMessage("rematch", { accept: true })
+            declarationArea.innerHTML = ""
+        })
+        const leaveButton = document.createElement("button")
+        leaveButton.textContent = "Leave table"
+        leaveButton.addEventListener("click", () => sendMessage("rematch", { accept: false }))
+        declarationArea.appendChild(acceptButton)
+        declarationArea.appendChild(leaveButton)
+        return
+    }
+    if (payload.pending_player_ids.length > 0) {
+        const names = payload.pending_player_ids.map((id) => {
+            const player = findPlayerInTeams(id)
+            return player ? player.name : id
+        })
+        statusMessage.textContent = `Waiting for ${names.join(", ")} to accept the next game...`
+    }
+}
+
+// handleTableClosed sends everyone back to the start once the table breaks up.
+function handleTableClosed(payload) {
+    const player = findPlayerInTeams(payload.player_id)
+    statusMessage.textContent = player ? `${player.name} left the table.` : "The table was closed."
+    localStorage.removeItem(sessionTokenKey) // Nothing left to rejoin
+    declarationArea.innerHTML = ""
     setTimeout(() => {
         showSection("initial-section")
+        matchInfoDiv.classList.add("hidden")
         myPlayerId = null // Reset player ID
         myPlayerName = null // Reset player name
         teamsInfo = null // Reset teams info
@@ -483,7 +1242,9 @@ function handleGameOver(payload) {
         roundOver = false // Reset round over flag
         roundOverPayload = null // Reset round over payload
         gameOver = false // Reset game over flag
-    }, 10000)
+        spectating = false // Reset spectator flag
+        document.querySelectorAll(".revealed-hand").forEach((el) => el.remove())
+    }, 5000)
 }
 
 // --- UI Rendering Functions ---
@@ -513,6 +1274,12 @@ function showSection(sectionId) {
     waitingSection.classList.add("hidden")
     gameContainer.classList.add("hidden")
 
+    // Chat is available in the lobby and at the table
+    chatSection.classList.toggle("hidden", sectionId === "initial-section")
+    if (sectionId === "initial-section") {
+        chatMessagesDiv.innerHTML = ""
+    }
+
     const sectionToShow = document.getElementById(sectionId)
     if (sectionToShow) {
         sectionToShow.classList.remove("hidden")
@@ -553,6 +1320,9 @@ function clearTrickDisplay() {
 }
 
 function renderDeclarations() {
+    // Start from scratch so repeated phase updates don't stack the options
+    declarationsSection.innerHTML = ""
+    declarationArea.innerHTML = ""
     if (canDeclare) {
         const declareButton = document.createElement("button")
         const napolaDeclarations = document.createElement("div")
@@ -565,24 +1335,20 @@ function renderDeclarations() {
         threeFourOfAKindDeclarations.classList.add("declarations-section-inner")
         napolaDeclarationsTitle.textContent = "Napola Declarations"
         threeFourOfAKindDeclarationsTitle.textContent = "Three or Four of a Kind Declarations"
-        declarations.forEach((declaration) => {
+        availableDeclarations.forEach((declaration) => {
             const declarationElement = document.createElement("img")
-            const type = declaration.type
-            let suit = ""
-            let rank = ""
             declarationElement.classList.add("card")
             declarationElement.style.cursor = "pointer"
-            declarationElement.src = declaration.image
-            if (declaration.type === "napola") {
-                suit = declaration.suit
+            if (declaration.declaration_type === "napola") {
+                declarationElement.src = `images/cards/${declaration.suit}_1.png`
                 napolaDeclarations.appendChild(declarationElement)
-            } else if (declaration.type === "three_or_four_of_kind") {
-                rank = declaration.rank
+            } else if (declaration.declaration_type === "three_or_four_of_kind") {
+                declarationElement.src = `images/cards/Bastoni_${declaration.rank}.png`
                 threeFourOfAKindDeclarations.appendChild(declarationElement)
             }
 
             declarationElement.addEventListener("click", () => {
-                sendMessage("declare", { declaration_type: type, suit: suit, rank: rank })
+                sendMessage("declare", declaration)
             })
         })
         declarationsSection.appendChild(napolaDeclarationsTitle)
@@ -595,15 +1361,62 @@ function renderDeclarations() {
             declarationsSection.style.display = "block"
         })
         declarationArea.appendChild(declareButton)
+
+        const doneButton = document.createElement("button")
+        doneButton.textContent = "No more declarations"
+        doneButton.classList.add("declare-button")
+        doneButton.addEventListener("click", () => {
+            sendMessage("declarations_done", {})
+        })
+        declarationArea.appendChild(doneButton)
     } else {
-        const declareButton = document.querySelector(".declare-button")
-        if (declareButton) {
-            declareButton.remove() // Remove the button if it exists
-        }
         declarationsSection.style.display = "none" // Hide the declaration area
     }
 }
 
+function handleDeclarationPhase(payload) {
+    // Everyone declares at the same time; declarations are announced once all are done
+    const pending = payload.pending_player_ids.includes(myPlayerId)
+    availableDeclarations = payload.available_declarations || []
+    canDeclare = pending
+    renderDeclarations()
+    if (pending) {
+        statusMessage.textContent = `Declare your combinations, then confirm you have no more (${payload.seconds_left}s left).`
+    } else {
+        const names = payload.pending_player_ids.map((id) => {
+            const player = findPlayerInTeams(id)
+            return player ? player.name : id
+        })
+        statusMessage.textContent = `Waiting for ${names.join(", ")} to finish declaring...`
+    }
+}
+
+function handleReadyCheck(payload) {
+    // The next round starts once everyone is ready or the time runs out
+    declarationArea.innerHTML = ""
+    if (payload.pending_player_ids.includes(myPlayerId) && !spectating) {
+        const readyButton = document.createElement("button")
+        readyButton.textContent = "Ready for the next round"
+        readyButton.addEventListener("click", () => {
+            sendMessage("ready", {})
+            declarationArea.innerHTML = ""
+        })
+        declarationArea.appendChild(readyButton)
+        statusMessage.textContent = `Round over. The next round starts in ${payload.seconds_left}s or when everyone is ready.`
+        return
+    }
+    const names = payload.pending_player_ids.map((id) => {
+        const player = findPlayerInTeams(id)
+        return player ? player.name : id
+    })
+    statusMessage.textContent = `Waiting for ${names.join(", ")} to be ready...`
+}
+
+function handleDeclarationRecorded(payload) {
+    const what = payload.declaration_type === "napola" ? `Napola ${payload.suit}` : `${payload.rank}s`
+    statusMessage.textContent = `${what} declared. It will be announced when everyone is done.`
+}
+
 function removeCardFromHand(cardToRemove) {
     const cardId = `${cardToRemove.Suit}-${cardToRemove.Rank}`
     const cardElement = playerHandDiv.querySelector(`[data-card-id="${cardId}"]`)
@@ -632,13 +1445,8 @@ function createCardElement(card, isTrickCard = false) {
     return cardElement
 }
 
-function highlightPlayableCards() {
-    const validMoves = trickCards.length === 0 ? handCards : handCards.filter((card) => trickCards[0].Suit === card.Suit)
-
-    if (validMoves.length === 0) {
-        validMoves.push(...handCards) // If no valid moves, all cards are playable
-    }
-
+function highlightPlayableCards(validMoves) {
+    // The server sends the legal cards with every turn
     removeHighlightedCards() // Clear previous highlights
 
     if (validMoves && validMoves.length > 0) {
@@ -660,17 +1468,40 @@ function removeHighlightedCards() {
 }
 
 function resetScores() {
-    // Reset scores for both teams to 0
+    // Reset the round score of every team to 0
     teamsInfo.forEach((team) => {
-        if (team.team_number === 1) {
-            team1ScoreCurrentRoundSpan.textContent = "0"
-        } else if (team.team_number === 2) {
-            team2ScoreCurrentRoundSpan.textContent = "0"
+        team.score = 0 // Reset score for each team
+        renderRoundScore(team)
+    })
+}
+
+function renderRoundScore(team) {
+    // Scores are kept in thirds of a point
+    const span = document.getElementById(`team${team.team_number}-score-current-round`)
+    if (span) {
+        span.textContent = team.score % 3 === 0 ? `${team.score / 3}` : `${team.score} / 3`
+    }
+}
+
+function setScoreLabels(players, teams) {
+    // Teams of one are labelled with the player's name instead of the team colour
+    const colours = ["Red", "Blue", "Green", "Yellow"]
+    teams.forEach((team) => {
+        const label = document.querySelector(`#team${team.team_number}-score span`)
+        if (!label) {
+            return
         }
+        const soloGame = teams.length === players.length && players.length > 2
+        label.textContent = soloGame ? `${team.players[0].name}: ` : `${colours[team.team_number - 1]}: `
     })
+}
 
-    teamsInfo.forEach((team) => {
-        team.score = 0 // Reset score for each team
+function setTotalScores(totalScores) {
+    totalScores.forEach((score, i) => {
+        const span = document.getElementById(`team${i + 1}-score-total`)
+        if (span) {
+            span.textContent = `${score}`
+        }
     })
 }
 
@@ -679,13 +1510,7 @@ function updateScoresAfterTrick(playerId, points) {
     teamsInfo.forEach((team) => {
         if (team.players.some((p) => p.id === playerId)) {
             team.score += points // Add points to the winning team
-            if (team.team_number === 1) {
-                const team1Score = team.score % 3 === 0 ? `${team.score / 3}` : `${team.score} / 3`
-                team1ScoreCurrentRoundSpan.textContent = `${team1Score}`
-            } else if (team.team_number === 2) {
-                const team2Score = team.score % 3 === 0 ? `${team.score / 3}` : `${team.score} / 3`
-                team2ScoreCurrentRoundSpan.textContent = `${team2Score}`
-            }
+            renderRoundScore(team)
         }
     })
 }
@@ -693,7 +1518,7 @@ function updateScoresAfterTrick(playerId, points) {
 function handleDeclarationConfirmation(payload) {
     updateScoresAfterDeclarationConfirmation(payload)
     // find player name ('You' if it's me)
-    const playerName = payload.player_id === myPlayerId ? "You" : teamsInfo
+    const playerName = payload.player_id === myPlayerId && !spectating ? "You" : teamsInfo
         .find((t) => t.players.some((p) => p.id === payload.player_id))
         .players.find((p) => p.id === payload.player_id).name
 
@@ -709,12 +1534,16 @@ function handleDeclarationConfirmation(payload) {
     }
 
     if (payload.points > 0) {
-        declarationInfo.textContent = message
+        // Declarations are announced one after another, so show them together
+        const line = document.createElement("div")
+        line.textContent = message
+        declarationInfo.appendChild(line)
         declarationInfo.style.display = "block"
         declarationInfo.classList.add("declaration-info")
-        setTimeout(() => {
+        clearTimeout(declarationInfoTimer)
+        declarationInfoTimer = setTimeout(() => {
             removeDeclarationInfo()
-        }, 5000) // Clear message after 5 seconds
+        }, 5000) // Clear messages 5 seconds after the last one
     }
 }
 
@@ -729,13 +1558,7 @@ function updateScoresAfterDeclarationConfirmation(payload) {
     teamsInfo.forEach((team) => {
         if (team.id === payload.team_id) {
             team.score += payload.points // Add points to the winning team
-            if (team.team_number === 1) {
-                const team1Score = team.score % 3 === 0 ? `${team.score / 3}` : `${team.score} / 3`
-                team1ScoreCurrentRoundSpan.textContent = `${team1Score}`
-            } else if (team.team_number === 2) {
-                const team2Score = team.score % 3 === 0 ? `${team.score / 3}` : `${team.score} / 3`
-                team2ScoreCurrentRoundSpan.textContent = `${team2Score}`
-            }
+            renderRoundScore(team)
         }
     })
 }
@@ -756,6 +1579,49 @@ function setupOpponentNames(players, teams) {
         return
     }
 
+    if (players.length === 2) {
+        // Heads-up: the only opponent sits across the table
+        const opponent = players.find((p) => p.id !== myPlayerId)
+        const opponentSpan = document.querySelector("#opponent-top .player-name")
+        opponentSpan.textContent = opponent.name
+        opponentSpan.classList.add(`team${getTeamIndexByPlayerId(opponent.id)}`)
+        playerPositions[opponent.id] = "opponent-top"
+        document.querySelector("#opponent-left .player-name").textContent = ""
+        document.querySelector("#opponent-right .player-name").textContent = ""
+        return
+    }
+
+    if (players.length === 4 && teams.length === 4) {
+        // A perdere: everyone plays alone, the seat opposite us is on top
+        const me = players.find((p) => p.id === myPlayerId)
+        const seats = { "opponent-left": 1, "opponent-top": 2, "opponent-right": 3 }
+        Object.entries(seats).forEach(([area, offset]) => {
+            const player = players.find((p) => p.position === (me.position + offset) % 4)
+            const span = document.querySelector(`#${area} .player-name`)
+            span.textContent = player.name
+            span.classList.add(`team${getTeamIndexByPlayerId(player.id)}`)
+            playerPositions[player.id] = area
+        })
+        return
+    }
+
+    if (players.length === 3) {
+        // Terziglio: everyone plays alone, next seat on the left and previous seat on the right
+        const me = players.find((p) => p.id === myPlayerId)
+        const left = players.find((p) => p.position === (me.position + 1) % 3)
+        const right = players.find((p) => p.position === (me.position + 2) % 3)
+        const leftSpan = document.querySelector("#opponent-left .player-name")
+        const rightSpan = document.querySelector("#opponent-right .player-name")
+        leftSpan.textContent = left.name
+        rightSpan.textContent = right.name
+        leftSpan.classList.add(`team${getTeamIndexByPlayerId(left.id)}`)
+        rightSpan.classList.add(`team${getTeamIndexByPlayerId(right.id)}`)
+        playerPositions[left.id] = "opponent-left"
+        playerPositions[right.id] = "opponent-right"
+        document.querySelector("#opponent-top .player-name").textContent = ""
+        return
+    }
+
     const partner = players.find(
         (p) => p.id !== myPlayerId && teams.some((t) => t.id === myTeamId && t.players.some((tp) => tp.id === p.id))
     )
@@ -803,13 +1669,62 @@ function setupOpponentNames(players, teams) {
     }
 }
 
+// --- Chat ---
+
+function sendChat() {
+    const text = chatInput.value.trim()
+    if (!text) {
+        return
+    }
+    sendMessage("chat", { channel: spectating ? "spectators" : chatChannelInput.value, text })
+    chatInput.value = ""
+}
+
+function appendChatMessage(message) {
+    chatNames[message.sender_id] = message.sender_name
+    const line = document.createElement("div")
+    const sender = document.createElement("span")
+    sender.classList.add("chat-sender")
+    sender.textContent = `${message.sender_name}${message.channel === "team" ? " (team)" : ""}: `
+    if (isHost && !spectating && message.sender_name !== myPlayerName) {
+        // The host mutes or unmutes someone by clicking their name
+        sender.classList.add("mutable")
+        sender.title = "Click to mute or unmute"
+        sender.addEventListener("click", () => {
+            sendMessage("mute_player", { player_id: message.sender_id, muted: !mutedPlayers.includes(message.sender_id) })
+        })
+    }
+    line.appendChild(sender)
+    line.append(message.text)
+    if (message.channel === "team") {
+        line.classList.add("chat-team")
+    }
+    chatMessagesDiv.appendChild(line)
+    chatMessagesDiv.scrollTop = chatMessagesDiv.scrollHeight
+}
+
+function handlePlayerMuted(payload) {
+    mutedPlayers = mutedPlayers.filter((id) => id !== payload.player_id)
+    if (payload.muted) {
+        mutedPlayers.push(payload.player_id)
+    }
+    const line = document.createElement("div")
+    const name = chatNames[payload.player_id] || "A player"
+    line.textContent = name === myPlayerName ? `You have been ${payload.muted ? "muted" : "unmuted"} by the host.` : `${name} was ${payload.muted ? "muted" : "unmuted"}.`
+    chatMessagesDiv.appendChild(line)
+}
+
 // --- Player Actions ---
 
 function playCard(card) {
-    if (playDisabled) {
+    if (playDisabled || spectating) {
         return
     }
-    sendMessage("play_card", { suit: card.Suit, rank: card.Rank })
+    const payload = { suit: card.Suit, rank: card.Rank }
+    if (selectedSignal && trickCards.length === 0) {
+        payload.signal = selectedSignal
+    }
+    sendMessage("play_card", payload)
 }
 
 // --- Utility Functions ---
@@ -830,6 +1745,14 @@ function compareCards(cardA, cardB) {
     return cardA.Order - cardB.Order // Then by rank (or order)
 }
 
+function findPlayerInTeams(playerId) {
+    if (!teamsInfo) {
+        return null
+    }
+    const team = teamsInfo.find((t) => t.players.some((p) => p.id === playerId))
+    return team ? team.players.find((p) => p.id === playerId) : null
+}
+
 function getTeamIndexByPlayerId(playerId) {
     for (let i = 0; i < teamsInfo.length; i++) {
         if (teamsInfo[i].players.some((p) => p.id === playerId)) {
@@ -839,6 +1762,26 @@ function getTeamIndexByPlayerId(playerId) {
     return -1 // Not found
 }
 
+// Fill the rules picker with the variants the server offers
+function loadRulesets() {
+    fetch("/api/rulesets")
+        .then((response) => response.json())
+        .then((rulesets) => {
+            rulesetInput.innerHTML = ""
+            rulesets.forEach((rules) => {
+                const option = document.createElement("option")
+                option.value = rules.name
+                option.textContent = `${rules.description} (${rules.player_counts.join("/")} players)`
+                rulesetInput.appendChild(option)
+            })
+        })
+        .catch((error) => console.error("Could not load rulesets:", error))
+}
+
+loadRulesets()
+loadLobbies()
+loadLeaderboard()
+
 // Keepalive using ping/pong
 setInterval(() => {
     if (ws && ws.readyState === WebSocket.OPEN) {